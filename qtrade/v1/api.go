@@ -0,0 +1,48 @@
+package qtrade
+
+import (
+	"context"
+)
+
+// PublicAPI covers the public qTrade endpoints, which need no credentials.
+type PublicAPI interface {
+	GetCommon(ctx context.Context) (*CommonData, error)
+	GetTicker(ctx context.Context, market Market) (*Ticker, error)
+	GetTickers(ctx context.Context) ([]Ticker, error)
+	GetCurrency(ctx context.Context, currency Currency) (*CurrencyData, error)
+	GetCurrencies(ctx context.Context) ([]CurrencyData, error)
+	GetMarket(ctx context.Context, market Market) (*GetMarketData, error)
+	GetMarkets(ctx context.Context) ([]MarketData, error)
+	GetMarketTrades(ctx context.Context, market Market) ([]PublicTrade, error)
+	GetOrderbook(ctx context.Context, market Market) (*Orderbook, error)
+	GetOHLCV(ctx context.Context, market Market, interval Interval, params map[string]string) ([]OHLCVSlice, error)
+}
+
+// PrivateAPI covers the authenticated qTrade endpoints of a single account.
+type PrivateAPI interface {
+	GetUserInfo(ctx context.Context) (*UserInfo, error)
+	GetBalances(ctx context.Context, params map[string]string) ([]Balance, error)
+	GetUserMarket(ctx context.Context, market Market, params map[string]string) (*UserMarketData, error)
+	GetOrders(ctx context.Context, params map[string]string) ([]Order, error)
+	GetOrder(ctx context.Context, id int) (*Order, error)
+	GetTrades(ctx context.Context, params map[string]string) ([]PrivateTrade, error)
+	CancelOrder(ctx context.Context, id int) error
+	Withdraw(ctx context.Context, address string, amount Decimal, currency Currency) (*WithdrawData, error)
+	GetWithdrawDetails(ctx context.Context, id int) (*WithdrawDetails, error)
+	GetWithdrawHistory(ctx context.Context, params map[string]string) ([]WithdrawDetails, error)
+	GetDeposit(ctx context.Context, id string) ([]DepositDetails, error)
+	GetDepositHistory(ctx context.Context, params map[string]string) ([]DepositDetails, error)
+	GetDepositAddress(ctx context.Context, currency Currency) (*DepositAddressData, error)
+	GetTransfers(ctx context.Context, params map[string]string) ([]Transfer, error)
+	CreateSellLimit(ctx context.Context, amount Decimal, market Market, price Decimal) (*Order, error)
+	CreateBuyLimit(ctx context.Context, amount Decimal, market Market, price Decimal) (*Order, error)
+}
+
+// API is the full qTrade API. It is implemented by *Client, and by the fakes
+// in the qtradetest package for testing code that uses the client.
+type API interface {
+	PublicAPI
+	PrivateAPI
+}
+
+var _ API = (*Client)(nil)
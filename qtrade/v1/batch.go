@@ -0,0 +1,131 @@
+package qtrade
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrBatchAborted is returned by PlaceOrders, in all-or-nothing mode, for the
+// orders that were cancelled or never placed because another order failed.
+var ErrBatchAborted = errors.New("order batch aborted")
+
+// PlaceOrdersOptions sets how PlaceOrders places a batch of orders.
+type PlaceOrdersOptions struct {
+	// Concurrency is the number of orders placed at once. It defaults to 4.
+	// Requests still wait for the client's rate limiter.
+	Concurrency int
+	// AllOrNothing cancels the orders that were placed if any order of the
+	// batch fails, and stops placing the others.
+	AllOrNothing bool
+	// RollBackTimeout bounds the cancellations made in all-or-nothing mode.
+	// They do not use the batch's context, whose cancellation may be what
+	// failed the batch. It defaults to 30 seconds.
+	RollBackTimeout time.Duration
+}
+
+// PlaceOrders places a batch of orders, and returns the placed orders and the
+// errors in slices aligned with requests. An order is nil if it was not
+// placed.
+//
+// In all-or-nothing mode, once an order fails, the orders not yet sent fail
+// with ErrBatchAborted, and those that were placed are cancelled, even if ctx
+// is done. A cancelled order is still returned, with an error wrapping
+// ErrBatchAborted. If it could not be cancelled, its error says why instead,
+// and the order may still be open.
+func (client *Client) PlaceOrders(ctx context.Context, requests []OrderRequest, opts PlaceOrdersOptions) ([]*Order, []error) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	orders := make([]*Order, len(requests))
+	errs := make([]error, len(requests))
+	sem := make(chan struct{}, concurrency)
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed bool
+	)
+
+	for i := range requests {
+		sem <- struct{}{}
+
+		mu.Lock()
+		aborted := opts.AllOrNothing && failed
+		mu.Unlock()
+
+		if aborted {
+			<-sem
+			errs[i] = ErrBatchAborted
+
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			orders[i], errs[i] = client.PlaceOrder(ctx, requests[i])
+
+			if errs[i] != nil {
+				mu.Lock()
+				failed = true
+				mu.Unlock()
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	if opts.AllOrNothing && failed {
+		timeout := opts.RollBackTimeout
+		if timeout <= 0 {
+			timeout = time.Second * 30
+		}
+
+		// the placed orders must be cancelled even if ctx is done
+		rollBackCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		client.rollBack(rollBackCtx, orders, errs, concurrency)
+	}
+
+	return orders, errs
+}
+
+// rollBack cancels the placed orders of a failed batch.
+func (client *Client) rollBack(ctx context.Context, orders []*Order, errs []error, concurrency int) {
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for i, order := range orders {
+		if order == nil {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int, order *Order) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := client.CancelOrder(ctx, order.ID); err != nil {
+				errs[i] = errors.Wrap(err, fmt.Sprintf("failed to roll back order %v", order.ID))
+				return
+			}
+
+			errs[i] = errors.Wrap(ErrBatchAborted, fmt.Sprintf("order %v cancelled", order.ID))
+		}(i, order)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,196 @@
+package qtrade
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// registerOrderCreation answers limit order requests with open orders with
+// increasing IDs, and with insufficient funds for orders priced at 0.5.
+func registerOrderCreation(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		nextID = 100
+	)
+
+	responder := func(orderType OrderType) httpmock.Responder {
+		return func(req *http.Request) (*http.Response, error) {
+			var body struct {
+				Amount   string `json:"amount"`
+				MarketID int    `json:"market_id"`
+				Price    string `json:"price"`
+			}
+
+			b, _ := ioutil.ReadAll(req.Body)
+			assert.NoError(t, json.Unmarshal(b, &body))
+
+			if body.Price == "0.50000000" {
+				return httpmock.NewStringResponse(400, `{"errors": [{"code": "insuff_funds", "title": "Insufficient funds"}]}`), nil
+			}
+
+			mu.Lock()
+			nextID++
+			id := nextID
+			mu.Unlock()
+
+			result := new(CreateOrderResult)
+			result.Data.Order = Order{
+				ID:                    id,
+				Market:                Market(body.MarketID),
+				MarketAmount:          MustParseDecimal(body.Amount),
+				MarketAmountRemaining: MustParseDecimal(body.Amount),
+				Open:                  true,
+				OrderType:             orderType,
+				Price:                 MustParseDecimal(body.Price),
+			}
+
+			return httpmock.NewJsonResponse(200, result)
+		}
+	}
+
+	httpmock.RegisterResponder("POST", "http://localhost/v1/user/buy_limit", responder(BuyLimit))
+	httpmock.RegisterResponder("POST", "http://localhost/v1/user/sell_limit", responder(SellLimit))
+	httpmock.RegisterResponder("POST", "http://localhost/v1/user/cancel_order", httpmock.NewStringResponder(200, ""))
+}
+
+func TestClient_PlaceOrders(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	registerTradableMarket()
+
+	registerOrderCreation(t)
+
+	requests := []OrderRequest{
+		{Market: LTC_BTC, Side: BuyLimit, Amount: MustParseDecimal("1"), Price: MustParseDecimal("0.01")},
+		{Market: LTC_BTC, Side: BuyLimit, Amount: MustParseDecimal("1"), Price: MustParseDecimal("0.5")},
+		{Market: LTC_BTC, Side: SellLimit, Amount: MustParseDecimal("2"), Price: MustParseDecimal("0.03")},
+		{Market: LTC_BTC, Side: "stop_loss", Amount: MustParseDecimal("1"), Price: MustParseDecimal("0.01")},
+	}
+
+	orders, errs := testClient.PlaceOrders(context.Background(), requests, PlaceOrdersOptions{})
+	if !assert.Len(t, orders, 4) || !assert.Len(t, errs, 4) {
+		return
+	}
+
+	assert.NoError(t, errs[0])
+	assert.Equal(t, MustParseDecimal("0.01"), orders[0].Price)
+
+	assert.True(t, IsInsufficientFunds(errs[1]))
+	assert.Nil(t, orders[1])
+
+	assert.NoError(t, errs[2])
+	assert.Equal(t, SellLimit, orders[2].OrderType)
+
+	assert.EqualError(t, errs[3], `unknown order side "stop_loss": invalid order`)
+	assert.Nil(t, orders[3])
+
+	assert.Equal(t, 0, httpmock.GetCallCountInfo()["POST http://localhost/v1/user/cancel_order"])
+}
+
+func TestClient_PlaceOrders_AllOrNothing(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	registerTradableMarket()
+
+	registerOrderCreation(t)
+
+	requests := []OrderRequest{
+		{Market: LTC_BTC, Side: BuyLimit, Amount: MustParseDecimal("1"), Price: MustParseDecimal("0.01")},
+		{Market: LTC_BTC, Side: BuyLimit, Amount: MustParseDecimal("1"), Price: MustParseDecimal("0.5")},
+		{Market: LTC_BTC, Side: BuyLimit, Amount: MustParseDecimal("1"), Price: MustParseDecimal("0.02")},
+	}
+
+	orders, errs := testClient.PlaceOrders(context.Background(), requests, PlaceOrdersOptions{
+		Concurrency:  1,
+		AllOrNothing: true,
+	})
+
+	// the first order is placed and then cancelled
+	if assert.NotNil(t, orders[0]) {
+		assert.True(t, errors.Is(errs[0], ErrBatchAborted))
+	}
+
+	assert.True(t, IsInsufficientFunds(errs[1]))
+
+	// the last order is never sent
+	assert.Nil(t, orders[2])
+	assert.Equal(t, ErrBatchAborted, errs[2])
+
+	assert.Equal(t, 2, httpmock.GetCallCountInfo()["POST http://localhost/v1/user/buy_limit"])
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["POST http://localhost/v1/user/cancel_order"])
+}
+
+func TestClient_PlaceOrders_FailedRollBack(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	registerTradableMarket()
+
+	registerOrderCreation(t)
+	httpmock.RegisterResponder("POST", "http://localhost/v1/user/cancel_order",
+		httpmock.NewStringResponder(400, `{"errors": [{"code": "order_closed", "title": "Order 101 is already closed"}]}`))
+
+	requests := []OrderRequest{
+		{Market: LTC_BTC, Side: SellLimit, Amount: MustParseDecimal("1"), Price: MustParseDecimal("0.01")},
+		{Market: LTC_BTC, Side: SellLimit, Amount: MustParseDecimal("1"), Price: MustParseDecimal("0.5")},
+	}
+
+	orders, errs := testClient.PlaceOrders(context.Background(), requests, PlaceOrdersOptions{AllOrNothing: true})
+
+	if assert.NotNil(t, orders[0]) {
+		assert.True(t, IsOrderClosed(errs[0]))
+		assert.False(t, errors.Is(errs[0], ErrBatchAborted))
+	}
+}
+
+func TestClient_PlaceOrders_CanceledContext(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	registerTradableMarket()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls int
+
+	httpmock.RegisterResponder("POST", "http://localhost/v1/user/buy_limit",
+		func(req *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				return httpmock.NewStringResponse(200, buyLimitData), nil
+			}
+
+			// the caller gives up while the second order is in flight
+			cancel()
+
+			return httpmock.NewStringResponse(400, `{"errors": [{"code": "insuff_funds", "title": "Insufficient funds"}]}`), nil
+		})
+	httpmock.RegisterResponder("POST", "http://localhost/v1/user/cancel_order", httpmock.NewStringResponder(200, ""))
+
+	requests := []OrderRequest{
+		{Market: LTC_BTC, Side: BuyLimit, Amount: MustParseDecimal("1"), Price: MustParseDecimal("0.01")},
+		{Market: LTC_BTC, Side: BuyLimit, Amount: MustParseDecimal("1"), Price: MustParseDecimal("0.02")},
+		{Market: LTC_BTC, Side: BuyLimit, Amount: MustParseDecimal("1"), Price: MustParseDecimal("0.03")},
+	}
+
+	orders, errs := testClient.PlaceOrders(ctx, requests, PlaceOrdersOptions{Concurrency: 1, AllOrNothing: true})
+
+	if assert.NotNil(t, orders[0]) {
+		assert.True(t, errors.Is(errs[0], ErrBatchAborted), "order 0: %v", errs[0])
+	}
+
+	assert.Error(t, errs[1])
+	assert.Equal(t, ErrBatchAborted, errs[2])
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["POST http://localhost/v1/user/cancel_order"])
+}
@@ -0,0 +1,149 @@
+package qtrade
+
+import (
+	"context"
+	"sync"
+)
+
+// CancelFilter selects the open orders cancelled by CancelAllOrders, and sets
+// how they are cancelled. The zero value cancels every open order.
+type CancelFilter struct {
+	// Market, if set, restricts the orders to one market.
+	Market Market
+	// Side, if set, restricts the orders to buy or sell orders.
+	Side OrderType
+	// MinPrice and MaxPrice, if not zero, restrict the orders to those priced
+	// within them, inclusive.
+	MinPrice Decimal
+	MaxPrice Decimal
+
+	// Concurrency is the number of orders cancelled at once. It defaults to 4.
+	// Requests made through a Client still wait for its rate limiter.
+	Concurrency int
+	// Verify lists the open orders again once all cancellations are done, and
+	// reports those that match the filter in CancelReport.Remaining.
+	Verify bool
+}
+
+func (filter CancelFilter) match(order Order) bool {
+	switch {
+	case filter.Market != 0 && order.Market != filter.Market:
+		return false
+	case filter.Side != "" && order.OrderType != filter.Side:
+		return false
+	case !filter.MinPrice.IsZero() && order.Price.Cmp(filter.MinPrice) < 0:
+		return false
+	case !filter.MaxPrice.IsZero() && order.Price.Cmp(filter.MaxPrice) > 0:
+		return false
+	}
+
+	return true
+}
+
+// CancelFailure is an order that could not be cancelled.
+type CancelFailure struct {
+	Order Order
+	Err   error
+}
+
+// CancelReport describes the outcome of CancelAllOrders. Orders are listed in
+// the order they were returned by the API, newest first.
+type CancelReport struct {
+	// Canceled holds the orders that were cancelled.
+	Canceled []Order
+	// AlreadyClosed holds the orders that were filled or cancelled before
+	// their cancellation was processed.
+	AlreadyClosed []Order
+	// Failed holds the orders whose cancellation failed for another reason.
+	Failed []CancelFailure
+	// Remaining holds the orders matching the filter that were still open
+	// afterwards, if the filter asked to verify.
+	Remaining []Order
+}
+
+// OK reports whether no cancellation failed and no matching order remains.
+func (report *CancelReport) OK() bool {
+	return len(report.Failed) == 0 && len(report.Remaining) == 0
+}
+
+// CancelAllOrders cancels the open orders that match filter, using the
+// CancelAllOrders function.
+func (client *Client) CancelAllOrders(ctx context.Context, filter CancelFilter) (*CancelReport, error) {
+	return CancelAllOrders(ctx, client, filter)
+}
+
+// CancelAllOrders cancels the open orders of api that match filter. It returns
+// an error only if the open orders could not be listed; the outcome of each
+// cancellation is in the report.
+func CancelAllOrders(ctx context.Context, api PrivateAPI, filter CancelFilter) (*CancelReport, error) {
+	orders, err := openOrders(ctx, api, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency := filter.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	errs := make([]error, len(orders))
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for i := range orders {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			errs[i] = api.CancelOrder(ctx, orders[i].ID)
+		}(i)
+	}
+
+	wg.Wait()
+
+	report := new(CancelReport)
+
+	for i, order := range orders {
+		switch err := errs[i]; {
+		case err == nil:
+			report.Canceled = append(report.Canceled, order)
+		case IsOrderClosed(err):
+			report.AlreadyClosed = append(report.AlreadyClosed, order)
+		default:
+			report.Failed = append(report.Failed, CancelFailure{Order: order, Err: err})
+		}
+	}
+
+	if filter.Verify {
+		report.Remaining, err = openOrders(ctx, api, filter)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// openOrders returns every open order of api that matches filter.
+func openOrders(ctx context.Context, api PrivateAPI, filter CancelFilter) ([]Order, error) {
+	open := true
+
+	all, err := NewOrdersIterator(api, OrdersQuery{Open: &open, Market: filter.Market}).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []Order
+
+	for _, order := range all {
+		if order.Open && filter.match(order) {
+			orders = append(orders, order)
+		}
+	}
+
+	return orders, nil
+}
@@ -0,0 +1,121 @@
+package qtrade
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_CancelAllOrders(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	open := map[int]Order{}
+
+	for _, order := range []Order{
+		{ID: 1, Market: LTC_BTC, OrderType: BuyLimit, Price: MustParseDecimal("0.01"), Open: true},
+		{ID: 2, Market: LTC_BTC, OrderType: BuyLimit, Price: MustParseDecimal("0.02"), Open: true},
+		{ID: 3, Market: LTC_BTC, OrderType: BuyLimit, Price: MustParseDecimal("0.03"), Open: true},
+		{ID: 4, Market: LTC_BTC, OrderType: BuyLimit, Price: MustParseDecimal("0.04"), Open: true},
+		{ID: 5, Market: LTC_BTC, OrderType: SellLimit, Price: MustParseDecimal("0.05"), Open: true},
+		{ID: 6, Market: ETH_BTC, OrderType: BuyLimit, Price: MustParseDecimal("0.02"), Open: true},
+	} {
+		open[order.ID] = order
+	}
+
+	var mu sync.Mutex
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/user/orders",
+		func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			assert.Equal(t, "true", req.URL.Query().Get("open"))
+			assert.Equal(t, "LTC_BTC", req.URL.Query().Get("market_string"))
+
+			result := new(GetOrdersResult)
+			result.Data.Orders = []Order{}
+
+			if req.URL.Query().Get("older_than") == "" {
+				for id := 6; id >= 1; id-- {
+					if order, ok := open[id]; ok {
+						result.Data.Orders = append(result.Data.Orders, order)
+					}
+				}
+			}
+
+			return httpmock.NewJsonResponse(200, result)
+		})
+
+	httpmock.RegisterResponder("POST", "http://localhost/v1/user/cancel_order",
+		func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			var body struct {
+				ID int `json:"id"`
+			}
+
+			b, _ := ioutil.ReadAll(req.Body)
+			assert.NoError(t, json.Unmarshal(b, &body))
+
+			switch body.ID {
+			case 3:
+				delete(open, 3)
+				return httpmock.NewStringResponse(400, `{"errors": [{"code": "order_closed", "title": "Order 3 is already closed"}]}`), nil
+			case 4:
+				return httpmock.NewStringResponse(500, ""), nil
+			}
+
+			delete(open, body.ID)
+
+			return httpmock.NewStringResponse(200, ""), nil
+		})
+
+	client, err := NewClient(testConfig, WithRetryPolicy(nil))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	report, err := client.CancelAllOrders(context.Background(), CancelFilter{
+		Market:      LTC_BTC,
+		Side:        BuyLimit,
+		MinPrice:    MustParseDecimal("0.02"),
+		Concurrency: 2,
+		Verify:      true,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []int{2}, orderIDs(report.Canceled))
+	assert.Equal(t, []int{3}, orderIDs(report.AlreadyClosed))
+
+	if assert.Len(t, report.Failed, 1) {
+		assert.Equal(t, 4, report.Failed[0].Order.ID)
+		assert.True(t, IsRetryable(report.Failed[0].Err))
+	}
+
+	assert.Equal(t, []int{4}, orderIDs(report.Remaining))
+	assert.False(t, report.OK())
+
+	assert.Contains(t, open, 1)
+	assert.Contains(t, open, 5)
+	assert.Equal(t, 3, httpmock.GetCallCountInfo()["POST http://localhost/v1/user/cancel_order"])
+}
+
+func TestCancelFilter_Match(t *testing.T) {
+	order := Order{Market: LTC_BTC, OrderType: SellLimit, Price: MustParseDecimal("0.05")}
+
+	assert.True(t, CancelFilter{}.match(order))
+	assert.True(t, CancelFilter{MinPrice: MustParseDecimal("0.05"), MaxPrice: MustParseDecimal("0.05")}.match(order))
+	assert.False(t, CancelFilter{MaxPrice: MustParseDecimal("0.04")}.match(order))
+	assert.False(t, CancelFilter{Side: BuyLimit}.match(order))
+	assert.False(t, CancelFilter{Market: ETH_BTC}.match(order))
+}
@@ -2,173 +2,287 @@ package qtrade
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
-	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 )
 
-var (
-	ErrTooManyRequests = errors.New("too many requests")
-	ErrHTTPRetryable   = errors.New("a retryable HTTP error occurred")
-)
-
 type Client struct {
-	Client *http.Client
-	Config Configuration
-	Auth   Auth
+	Client      *http.Client
+	Config      Configuration
+	Auth        Auth
+	RetryPolicy RetryPolicy
+
+	limiter   *rateLimiter
+	userAgent string
+	logger    Logger
+	registry  *Registry
+
+	// tradable holds the markets that GetMarket listed as tradable, so that
+	// PlaceOrder checks each market once when no registry lists it.
+	tradableMu sync.Mutex
+	tradable   map[Market]bool
 }
 
-func NewClient(config Configuration) (*Client, error) {
-	client := &http.Client{
-		Timeout: config.Timeout,
-	}
-
+// NewClient creates a Client from config. The HTTP client, retry policy and
+// other settings derived from config can be overridden with options.
+func NewClient(config Configuration, opts ...Option) (*Client, error) {
 	auth, err := AuthFromKeypair(config.HMACKeypair)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Client{
-		Client: client,
-		Config: config,
-		Auth:   *auth,
-	}, nil
+	client := &Client{
+		Client: &http.Client{
+			Timeout: config.Timeout,
+		},
+		Config:      config,
+		Auth:        *auth,
+		RetryPolicy: NewBackoffPolicy(config.MaxRetries, config.Backoff),
+		limiter:     newRateLimiter(),
+	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	return client, nil
+}
+
+// NewPublicClient creates a Client without credentials, for market data
+// services that only call public endpoints. Its requests are not signed, and
+// private endpoints return ErrNoCredentials without contacting the API.
+func NewPublicClient(config Configuration, opts ...Option) *Client {
+	client := &Client{
+		Client: &http.Client{
+			Timeout: config.Timeout,
+		},
+		Config:      config,
+		RetryPolicy: NewBackoffPolicy(config.MaxRetries, config.Backoff),
+		limiter:     newRateLimiter(),
+	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	return client
 }
 
-func (client *Client) generateHMAC(req *http.Request) (string, string, error) {
+func (client *Client) hasCredentials() bool {
+	return client.Auth.KeyID != "" && client.Auth.Key != ""
+}
+
+func (client *Client) generateHMAC(req *http.Request, body []byte) (string, string, error) {
 	timestamp := fmt.Sprintf("%v", time.Now().Unix())
 
-	reqDetails := bytes.NewBufferString(req.Method)
+	return client.sign(req.Method, req.URL.RequestURI(), timestamp, body), timestamp, nil
+}
+
+// sign builds the qTrade HMAC-SHA256 Authorization header value for a request
+// with the given method, path and query, timestamp and body.
+func (client *Client) sign(method, requestURI, timestamp string, body []byte) string {
+	reqDetails := bytes.NewBufferString(method)
 	reqDetails.WriteString("\n")
-	reqDetails.WriteString(req.URL.RequestURI())
+	reqDetails.WriteString(requestURI)
 	reqDetails.WriteString("\n")
 	reqDetails.WriteString(timestamp)
 	reqDetails.WriteString("\n")
-
-	if req.Body != nil {
-		bodyBytes := make([]byte, 0)
-
-		_, err := req.Body.Read(bodyBytes)
-		if err != nil {
-			return "", "", err
-		}
-
-		reqDetails.Write(bodyBytes)
-	}
-
+	reqDetails.Write(body)
 	reqDetails.WriteString("\n")
 	reqDetails.WriteString(client.Auth.Key)
 
 	hash := sha256.Sum256(reqDetails.Bytes())
 
-	hmac := "HMAC-SHA256 " +
+	return "HMAC-SHA256 " +
 		client.Auth.KeyID + ":" +
 		base64.StdEncoding.EncodeToString(hash[:])
+}
 
-	return hmac, timestamp, nil
+// apiRequest describes a single qTrade API call. Every endpoint goes through
+// doRequest with one of these, so encoding, signing, retries and error
+// handling are the same for all of them.
+type apiRequest struct {
+	method string
+	path   string
+	params map[string]string
+	body   interface{}
+	// idempotent marks POST calls that are safe to repeat. GET calls always are.
+	idempotent bool
 }
 
-func (client *Client) doRequest(req *http.Request, result interface{}, queryParams map[string]string) error {
-	retries := 0
+// private reports whether the call needs credentials. All authenticated
+// qTrade endpoints live under /v1/user/.
+func (r apiRequest) private() bool {
+	return strings.HasPrefix(r.path, "/v1/user/")
+}
 
-	q := req.URL.Query()
+func (r apiRequest) info() RequestInfo {
+	return RequestInfo{
+		Method:     r.method,
+		Path:       r.path,
+		Idempotent: r.idempotent || r.method == "GET",
+	}
+}
 
-	for k, v := range queryParams {
-		q.Add(k, v)
+// encodeBody marshals the request body once, so the exact same bytes are
+// signed and sent on every attempt.
+func (r apiRequest) encodeBody() ([]byte, error) {
+	if r.body == nil {
+		return nil, nil
 	}
 
-	req.URL.RawQuery = q.Encode()
+	return json.Marshal(r.body)
+}
 
-	for retries <= client.Config.MaxRetries {
-		auth, timestamp, err := client.generateHMAC(req)
-		if err != nil {
-			return errors.Wrap(err, "could not generate HMAC")
-		}
+// newRequest builds a fresh signed HTTP request for a single attempt.
+func (client *Client) newRequest(ctx context.Context, r apiRequest, body []byte) (*http.Request, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
 
-		req.Header.Set("Authorization", auth)
-		req.Header.Set("HMAC-Timestamp", timestamp)
+	req, err := http.NewRequestWithContext(ctx, r.method, client.Config.Endpoint+r.path, bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create HTTP request")
+	}
 
-		resp, err := client.Client.Do(req)
-		if err != nil {
-			return errors.Wrap(err, "could not complete HTTP request")
+	if len(r.params) > 0 {
+		q := req.URL.Query()
+
+		for k, v := range r.params {
+			q.Add(k, v)
 		}
 
-		err = checkForError(resp)
-		// retry, if applicable
-		switch {
-		case errors.Is(err, ErrTooManyRequests) && retries < client.Config.MaxRetries:
-			retries++
+		req.URL.RawQuery = q.Encode()
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if client.userAgent != "" {
+		req.Header.Set("User-Agent", client.userAgent)
+	}
+
+	if !client.hasCredentials() {
+		return req, nil
+	}
+
+	auth, timestamp, err := client.generateHMAC(req, body)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not generate HMAC")
+	}
+
+	req.Header.Set("Authorization", auth)
+	req.Header.Set("HMAC-Timestamp", timestamp)
 
-			reset, atoiErr := strconv.Atoi(resp.Header.Get("x-ratelimit-reset"))
-			if atoiErr != nil {
-				return errors.Wrap(atoiErr, "could not parse ratelimit reset header")
-			}
+	return req, nil
+}
 
-			time.Sleep(time.Duration(reset) * time.Second)
+func (client *Client) doRequest(ctx context.Context, r apiRequest, result interface{}) error {
+	if r.private() && !client.hasCredentials() {
+		return ErrNoCredentials
+	}
 
-			continue
+	body, err := r.encodeBody()
+	if err != nil {
+		return errors.Wrap(err, "could not encode request body")
+	}
+
+	for retries := 0; ; retries++ {
+		err = client.limiter.wait(ctx)
+		if err != nil {
+			return errors.Wrap(err, "could not wait for rate limit")
+		}
 
-		case errors.Is(err, ErrHTTPRetryable) && retries < client.Config.MaxRetries:
-			retries++
-			time.Sleep(client.Config.Backoff)
-			continue
+		resp, err := client.attempt(ctx, r, body, result)
+		client.limiter.update(resp)
 
-		case err != nil:
+		if err == nil || client.RetryPolicy == nil {
 			return err
 		}
 
-		b, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return errors.Wrap(err, "could not read response body")
+		delay, retry := client.RetryPolicy.Retry(r.info(), retries, err)
+		if !retry {
+			return err
 		}
 
-		err = json.Unmarshal(b, result)
-		if err != nil {
-			return errors.Wrap(err, "could not unmarshal request result")
+		client.logf("qtrade: retrying %s %s in %v after error: %v", r.method, r.path, delay, err)
+
+		if sleepErr := sleepContext(ctx, delay); sleepErr != nil {
+			return errors.Wrap(sleepErr, "could not wait to retry request")
 		}
+	}
+}
 
-		return nil
+// attempt sends a single request and decodes the response into result, if
+// result is not nil. The response body is always closed before it returns.
+func (client *Client) attempt(ctx context.Context, r apiRequest, body []byte, result interface{}) (*http.Response, error) {
+	req, err := client.newRequest(ctx, r, body)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
-}
+	resp, err := client.Client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not complete HTTP request")
+	}
 
-func checkForError(resp *http.Response) error {
-	if resp.StatusCode == http.StatusTooManyRequests {
-		return ErrTooManyRequests
+	defer resp.Body.Close()
+
+	err = checkForError(resp)
+	if err != nil {
+		return resp, err
 	}
 
-	if resp.StatusCode == http.StatusInternalServerError || resp.StatusCode == http.StatusServiceUnavailable {
-		return ErrHTTPRetryable
+	if result == nil {
+		return resp, nil
 	}
 
-	if resp.StatusCode >= 400 {
-		b, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return errors.Wrap(err, "failed to read HTTP response body with error "+resp.Status)
-		}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp, errors.Wrap(err, "could not read response body")
+	}
 
-		apiErrors := new(ErrorResult)
+	err = json.Unmarshal(b, result)
+	if err != nil {
+		return resp, errors.Wrap(err, "could not unmarshal request result")
+	}
 
-		err = json.Unmarshal(b, apiErrors)
-		if err != nil {
-			return fmt.Errorf("got API error with bad JSON: %s: %s", resp.Status, b)
-		}
+	return resp, nil
+}
 
-		resultErr := errors.New("API response: " + resp.Status)
-		for _, thisErr := range apiErrors.Errors {
-			resultErr = errors.Wrap(resultErr, thisErr.Error())
-		}
+func checkForError(resp *http.Response) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read HTTP response body with error "+resp.Status)
+	}
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       b,
+	}
 
-		return resultErr
+	apiErrors := new(ErrorResult)
+	if json.Unmarshal(b, apiErrors) == nil {
+		apiErr.Errors = apiErrors.Errors
 	}
 
-	return nil
+	return apiErr
 }
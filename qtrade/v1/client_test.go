@@ -27,26 +27,47 @@ func TestClient_generateHMAC(t *testing.T) {
 	testCases := []struct {
 		name     string
 		hmac     string
+		method   string
 		url      string
+		body     string
 		wantHMAC string
 	}{
 		{
 			name:     "no query string",
 			hmac:     "256:vwj043jtrw4o5igw4oi5jwoi45g",
+			method:   "GET",
 			url:      "http://google.com/",
 			wantHMAC: "HMAC-SHA256 256:iyfC4n+bE+3hLgMJns1Z67FKA7O5qm5PgDvZHGraMTQ=",
 		},
 		{
 			name:     "with query string",
 			hmac:     "1:1111111111111111111111111111111111111111111111111111111111111111",
+			method:   "GET",
 			url:      "https://api.qtrade.io/v1/user/orders?open=false",
 			wantHMAC: "HMAC-SHA256 1:4S8CauoSJcBbQsdcqpqvzN/aFyVJgADXU05eppDxiFA=",
 		},
+		{
+			name:     "cancel order body",
+			hmac:     "1:1111111111111111111111111111111111111111111111111111111111111111",
+			method:   "POST",
+			url:      "https://api.qtrade.io/v1/user/cancel_order",
+			body:     `{"id":109}`,
+			wantHMAC: "HMAC-SHA256 1:zLyj/MHRm3udvTyVIgE5s3FU0N9TtI5y1yjI98JFC0A=",
+		},
+		{
+			name:     "buy limit body",
+			hmac:     "1:1111111111111111111111111111111111111111111111111111111111111111",
+			method:   "POST",
+			url:      "https://api.qtrade.io/v1/user/buy_limit",
+			body:     `{"amount":"10.00000000","market_id":1,"price":"0.10000000"}`,
+			wantHMAC: "HMAC-SHA256 1:m9MWP879cs17xhbVjb5nt5lmAkoPC7QolgBXKpGWDAQ=",
+		},
 	}
 
 	monkey.Patch(time.Now, func() time.Time {
 		return time.Unix(12345, 0)
 	})
+	defer monkey.UnpatchAll()
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -57,9 +78,9 @@ func TestClient_generateHMAC(t *testing.T) {
 					Timeout:     time.Second * 10,
 				})
 
-			req, err := http.NewRequest("GET", tc.url, nil)
+			req, err := http.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
 			if assert.NoError(t, err) {
-				gotHMAC, _, gotErr := client.generateHMAC(req)
+				gotHMAC, _, gotErr := client.generateHMAC(req, []byte(tc.body))
 				if assert.NoError(t, gotErr) {
 					assert.Equal(t, tc.wantHMAC, gotHMAC)
 				}
@@ -68,11 +89,54 @@ func TestClient_generateHMAC(t *testing.T) {
 	}
 }
 
+func TestClient_RetryReplaysBody(t *testing.T) {
+	var bodies []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/v1/market/LTC_BTC" {
+			w.Write([]byte(tradableMarketData))
+			return
+		}
+
+		b, _ := io.ReadAll(req.Body)
+		bodies = append(bodies, string(b))
+
+		want := testClient.sign(req.Method, req.URL.RequestURI(), req.Header.Get("HMAC-Timestamp"), b)
+		assert.Equal(t, want, req.Header.Get("Authorization"))
+
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Write([]byte(buyLimitData))
+	}))
+
+	defer server.Close()
+
+	retryClient, _ := NewClient(
+		Configuration{
+			HMACKeypair: "1:1111111111111111111111111111111111111111111111111111111111111111",
+			Endpoint:    server.URL,
+			Timeout:     time.Second * 10,
+			Backoff:     time.Millisecond,
+			MaxRetries:  1,
+		})
+
+	retryClient.RetryPolicy.(*BackoffPolicy).RetryUnsafe = true
+
+	_, err := retryClient.CreateBuyLimit(context.Background(), DecimalFromInt(10), LTC_BTC, MustParseDecimal("0.1"))
+	if assert.NoError(t, err) {
+		wantBody := `{"amount":"10.00000000","market_id":1,"price":"0.10000000"}`
+		assert.Equal(t, []string{wantBody, wantBody}, bodies)
+	}
+}
+
 func TestClient_checkForError(t *testing.T) {
 	testCases := []struct {
 		name    string
 		resp    *http.Response
-		wantErr bool
+		wantErr *APIError
 		errMsg  string
 	}{
 		{
@@ -82,8 +146,12 @@ func TestClient_checkForError(t *testing.T) {
 				StatusCode: 418,
 				Body:       io.NopCloser(strings.NewReader("short and stout")),
 			},
-			wantErr: true,
-			errMsg:  "got API error with bad JSON: 418 I'm a teapot: short and stout",
+			wantErr: &APIError{
+				StatusCode: 418,
+				Status:     "418 I'm a teapot",
+				Body:       []byte("short and stout"),
+			},
+			errMsg: "API response: 418 I'm a teapot: short and stout",
 		},
 		{
 			name: "error with valid JSON",
@@ -92,17 +160,27 @@ func TestClient_checkForError(t *testing.T) {
 				StatusCode: 403,
 				Body:       io.NopCloser(strings.NewReader(`{"errors": [{"code": "invalid_auth","title": "Invalid HMAC signature"}]}`)),
 			},
-			wantErr: true,
-			errMsg:  "Invalid HMAC signature: API response: 403 Forbidden",
+			wantErr: &APIError{
+				StatusCode: 403,
+				Status:     "403 Forbidden",
+				Body:       []byte(`{"errors": [{"code": "invalid_auth","title": "Invalid HMAC signature"}]}`),
+				Errors: []Error{
+					{
+						Code:  "invalid_auth",
+						Title: "Invalid HMAC signature",
+					},
+				},
+			},
+			errMsg: "API response: 403 Forbidden: Invalid HMAC signature",
 		},
 		{
 			name: "non-error response",
 			resp: &http.Response{
-				Status:     "403 Forbidden",
-				StatusCode: 403,
-				Body:       io.NopCloser(strings.NewReader(`{"errors": [{"code": "invalid_auth","title": "Invalid HMAC signature"}]}`)),
+				Status:     "200 OK",
+				StatusCode: 200,
+				Body:       io.NopCloser(strings.NewReader(`{"data": {}}`)),
 			},
-			wantErr: false,
+			wantErr: nil,
 		},
 	}
 
@@ -110,9 +188,13 @@ func TestClient_checkForError(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			err := checkForError(tc.resp)
 
-			if tc.wantErr {
-				assert.Equal(t, tc.errMsg, err.Error())
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+				return
 			}
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.errMsg, err.Error())
 		})
 	}
 }
@@ -167,3 +249,101 @@ func TestClient_RateLimit(t *testing.T) {
 
 	assert.Equal(t, 2, callCount)
 }
+
+type closeTracker struct {
+	io.Reader
+	closed *int
+}
+
+func (c closeTracker) Close() error {
+	*c.closed++
+	return nil
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestClient_CancelOrderPipeline(t *testing.T) {
+	calls, closed := 0, 0
+
+	pipelineClient, _ := NewClient(
+		Configuration{
+			HMACKeypair: "1:1111111111111111111111111111111111111111111111111111111111111111",
+			Endpoint:    "http://localhost",
+			Timeout:     time.Second * 10,
+			Backoff:     time.Millisecond,
+			MaxRetries:  1,
+		})
+
+	pipelineClient.Client.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+
+		b, _ := io.ReadAll(req.Body)
+		assert.Equal(t, `{"id":109}`, string(b))
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+
+		status := http.StatusOK
+		if calls == 1 {
+			status = http.StatusServiceUnavailable
+		}
+
+		return &http.Response{
+			StatusCode: status,
+			Status:     http.StatusText(status),
+			Header:     http.Header{},
+			Body:       closeTracker{Reader: strings.NewReader(""), closed: &closed},
+		}, nil
+	})
+
+	err := pipelineClient.CancelOrder(context.Background(), 109)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 2, closed)
+}
+
+func TestClient_WithdrawError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "http://localhost/v1/user/withdraw",
+		httpmock.NewStringResponder(400, `{"errors": [{"code": "invalid_address","title": "Invalid address"}]}`))
+
+	got, err := testClient.Withdraw(context.Background(), "abcd", DecimalFromInt(20), BTC)
+	assert.Error(t, err)
+	assert.Nil(t, got)
+}
+
+func TestNewPublicClient(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/tickers",
+		func(req *http.Request) (*http.Response, error) {
+			assert.Empty(t, req.Header.Get("Authorization"))
+			assert.Empty(t, req.Header.Get("HMAC-Timestamp"))
+
+			return httpmock.NewStringResponse(200, tickersTestData), nil
+		})
+
+	publicClient := NewPublicClient(Configuration{
+		Endpoint: "http://localhost",
+		Timeout:  time.Second * 10,
+	})
+
+	tickers, err := publicClient.GetTickers(context.Background())
+	if assert.NoError(t, err) {
+		assert.Len(t, tickers, 2)
+	}
+
+	_, err = publicClient.GetUserInfo(context.Background())
+	assert.ErrorIs(t, err, ErrNoCredentials)
+
+	err = publicClient.CancelOrder(context.Background(), 109)
+	assert.ErrorIs(t, err, ErrNoCredentials)
+
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
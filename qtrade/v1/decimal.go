@@ -0,0 +1,384 @@
+package qtrade
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxDecimalExponent bounds the exponent accepted by ParseDecimal, so that a
+// hostile input like "1e999999999" cannot allocate a huge number.
+const maxDecimalExponent = 1000
+
+var bigOne = big.NewInt(1)
+
+// Decimal is an exact decimal number, used for prices, amounts, fees and
+// balances. Unlike float64 it can hold every value the exchange deals in,
+// including the 30 decimal places of NANO.
+//
+// Decimals are immutable, and the zero value is 0. Equal numbers are equal
+// with ==, whatever their original formatting, so a Decimal can be used as a
+// map key.
+//
+// A Decimal encodes to JSON as a string, like the qTrade API sends it, and
+// decodes from a string, a number or null.
+type Decimal struct {
+	// s is the canonical form: no exponent, no leading zeros in the integer
+	// part, no trailing zeros in the fraction, and "" for zero.
+	s string
+}
+
+// ParseDecimal parses a decimal number such as "12", "-0.00000033" or "1e-8".
+func ParseDecimal(s string) (Decimal, error) {
+	value, scale, ok := parseDecimal(s)
+	if !ok {
+		return Decimal{}, errors.Errorf("invalid decimal %q", s)
+	}
+
+	return newDecimal(value, scale), nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if s is not a valid decimal.
+// It is intended for constants in code and tests.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// DecimalFromInt returns i as a Decimal.
+func DecimalFromInt(i int64) Decimal {
+	return newDecimal(big.NewInt(i), 0)
+}
+
+// DecimalFromFloat returns the shortest decimal that converts back to f. It
+// panics if f is NaN or infinite.
+func DecimalFromFloat(f float64) Decimal {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		panic("qtrade: cannot convert " + strconv.FormatFloat(f, 'g', -1, 64) + " to a decimal")
+	}
+
+	return MustParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// parseDecimal returns the unscaled value and scale of s, so that s equals
+// value * 10^-scale.
+func parseDecimal(s string) (*big.Int, int, bool) {
+	mantissa, exponent := s, 0
+
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil || e > maxDecimalExponent || e < -maxDecimalExponent {
+			return nil, 0, false
+		}
+
+		mantissa, exponent = s[:i], e
+	}
+
+	sign := ""
+	if strings.HasPrefix(mantissa, "-") || strings.HasPrefix(mantissa, "+") {
+		sign, mantissa = mantissa[:1], mantissa[1:]
+	}
+
+	integer, fraction := mantissa, ""
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		integer, fraction = mantissa[:i], mantissa[i+1:]
+	}
+
+	digits := integer + fraction
+	if digits == "" {
+		return nil, 0, false
+	}
+
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return nil, 0, false
+		}
+	}
+
+	value, ok := new(big.Int).SetString(sign+digits, 10)
+
+	return value, len(fraction) - exponent, ok
+}
+
+// newDecimal returns value * 10^-scale in canonical form.
+func newDecimal(value *big.Int, scale int) Decimal {
+	if value.Sign() == 0 {
+		return Decimal{}
+	}
+
+	digits := new(big.Int).Abs(value).String()
+
+	if scale <= 0 {
+		digits += strings.Repeat("0", -scale)
+	} else {
+		if len(digits) <= scale {
+			digits = strings.Repeat("0", scale-len(digits)+1) + digits
+		}
+
+		point := len(digits) - scale
+		digits = strings.TrimRight(digits[:point]+"."+digits[point:], "0")
+		digits = strings.TrimSuffix(digits, ".")
+	}
+
+	if value.Sign() < 0 {
+		digits = "-" + digits
+	}
+
+	return Decimal{s: digits}
+}
+
+// parts returns the unscaled value and scale of d. The value is a new big.Int
+// that the caller may modify.
+func (d Decimal) parts() (*big.Int, int) {
+	if d.s == "" {
+		return new(big.Int), 0
+	}
+
+	value, scale, _ := parseDecimal(d.s)
+
+	return value, scale
+}
+
+// align returns the unscaled values of x and y at a common scale.
+func align(x, y Decimal) (*big.Int, *big.Int, int) {
+	xValue, xScale := x.parts()
+	yValue, yScale := y.parts()
+
+	if xScale < yScale {
+		xValue.Mul(xValue, pow10(yScale-xScale))
+		xScale = yScale
+	} else if yScale < xScale {
+		yValue.Mul(yValue, pow10(xScale-yScale))
+	}
+
+	return xValue, yValue, xScale
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+type roundingMode int
+
+const (
+	roundHalfUp roundingMode = iota
+	roundDown
+	roundUp
+)
+
+// quo divides num by den, rounding the quotient according to mode. Rounding
+// up and halves go away from zero, and rounding down goes towards zero.
+func quo(num, den *big.Int, mode roundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 || mode == roundDown {
+		return q
+	}
+
+	if mode == roundHalfUp {
+		twice := new(big.Int).Abs(r)
+		twice.Lsh(twice, 1)
+
+		if twice.Cmp(new(big.Int).Abs(den)) < 0 {
+			return q
+		}
+	}
+
+	if num.Sign() != den.Sign() {
+		return q.Sub(q, bigOne)
+	}
+
+	return q.Add(q, bigOne)
+}
+
+// String returns d in plain notation, without an exponent or trailing zeros.
+func (d Decimal) String() string {
+	if d.s == "" {
+		return "0"
+	}
+
+	return d.s
+}
+
+// StringFixed returns d rounded to places decimal places, padded with zeros to
+// exactly that many.
+func (d Decimal) StringFixed(places int) string {
+	s := d.Round(places).String()
+	if places <= 0 {
+		return s
+	}
+
+	point := strings.IndexByte(s, '.')
+	if point < 0 {
+		return s + "." + strings.Repeat("0", places)
+	}
+
+	return s + strings.Repeat("0", places-(len(s)-point-1))
+}
+
+// Float64 returns the float64 nearest to d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+
+	return f
+}
+
+// Add returns d + y.
+func (d Decimal) Add(y Decimal) Decimal {
+	x, y2, scale := align(d, y)
+
+	return newDecimal(x.Add(x, y2), scale)
+}
+
+// Sub returns d - y.
+func (d Decimal) Sub(y Decimal) Decimal {
+	x, y2, scale := align(d, y)
+
+	return newDecimal(x.Sub(x, y2), scale)
+}
+
+// Mul returns d * y.
+func (d Decimal) Mul(y Decimal) Decimal {
+	xValue, xScale := d.parts()
+	yValue, yScale := y.parts()
+
+	return newDecimal(xValue.Mul(xValue, yValue), xScale+yScale)
+}
+
+// Div returns d / y rounded to places decimal places, with halves rounded away
+// from zero. It panics if y is zero.
+func (d Decimal) Div(y Decimal, places int) Decimal {
+	xValue, xScale := d.parts()
+	yValue, yScale := y.parts()
+
+	if yValue.Sign() == 0 {
+		panic("qtrade: decimal division by zero")
+	}
+
+	// d / y * 10^places = xValue * 10^(places + yScale - xScale) / yValue
+	shift := places + yScale - xScale
+	if shift >= 0 {
+		xValue.Mul(xValue, pow10(shift))
+	} else {
+		yValue.Mul(yValue, pow10(-shift))
+	}
+
+	return newDecimal(quo(xValue, yValue, roundHalfUp), places)
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	switch {
+	case d.s == "":
+		return d
+	case d.s[0] == '-':
+		return Decimal{s: d.s[1:]}
+	default:
+		return Decimal{s: "-" + d.s}
+	}
+}
+
+// Abs returns the absolute value of d.
+func (d Decimal) Abs() Decimal {
+	if d.Sign() < 0 {
+		return d.Neg()
+	}
+
+	return d
+}
+
+// Cmp returns -1, 0 or +1 depending on whether d is less than, equal to or
+// greater than y.
+func (d Decimal) Cmp(y Decimal) int {
+	x, y2, _ := align(d, y)
+
+	return x.Cmp(y2)
+}
+
+// Sign returns -1, 0 or +1 depending on whether d is negative, zero or positive.
+func (d Decimal) Sign() int {
+	switch {
+	case d.s == "":
+		return 0
+	case d.s[0] == '-':
+		return -1
+	default:
+		return 1
+	}
+}
+
+// IsZero reports whether d is zero.
+func (d Decimal) IsZero() bool {
+	return d.s == ""
+}
+
+// Places returns the number of decimal places needed to write d exactly.
+func (d Decimal) Places() int {
+	_, scale := d.parts()
+
+	return scale
+}
+
+// Round rounds d to places decimal places, with halves rounded away from zero.
+func (d Decimal) Round(places int) Decimal {
+	return d.round(places, roundHalfUp)
+}
+
+// RoundDown rounds d towards zero to places decimal places.
+func (d Decimal) RoundDown(places int) Decimal {
+	return d.round(places, roundDown)
+}
+
+// RoundUp rounds d away from zero to places decimal places.
+func (d Decimal) RoundUp(places int) Decimal {
+	return d.round(places, roundUp)
+}
+
+func (d Decimal) round(places int, mode roundingMode) Decimal {
+	value, scale := d.parts()
+	if scale <= places {
+		return d
+	}
+
+	return newDecimal(quo(value, pow10(scale-places), mode), places)
+}
+
+// MarshalJSON encodes d as a JSON string.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON decodes d from a JSON string or number. null and the empty
+// string decode as zero.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		*d = Decimal{}
+
+		return nil
+	}
+
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+		if s == "" {
+			*d = Decimal{}
+
+			return nil
+		}
+	}
+
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode decimal")
+	}
+
+	*d = parsed
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package qtrade
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDecimal(t *testing.T) {
+	testCases := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "0", want: "0"},
+		{in: "-0.000", want: "0"},
+		{in: "12", want: "12"},
+		{in: "0012.3400", want: "12.34"},
+		{in: "+5", want: "5"},
+		{in: ".5", want: "0.5"},
+		{in: "5.", want: "5"},
+		{in: "-0.00000033", want: "-0.00000033"},
+		{in: "1e-8", want: "0.00000001"},
+		{in: "1.5E3", want: "1500"},
+		{in: "0.000000000000000000000000000001", want: "0.000000000000000000000000000001"},
+		{in: "", wantErr: true},
+		{in: ".", wantErr: true},
+		{in: "1.2.3", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "1_000", wantErr: true},
+		{in: "1e", wantErr: true},
+		{in: "1e99999", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := ParseDecimal(tc.in)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.want, got.String())
+			}
+		})
+	}
+}
+
+func TestDecimal_Equality(t *testing.T) {
+	assert.True(t, MustParseDecimal("0.50") == MustParseDecimal(".5"))
+	assert.True(t, Decimal{} == MustParseDecimal("0.0"))
+
+	m := map[Decimal]int{MustParseDecimal("1.10"): 1}
+	assert.Equal(t, 1, m[MustParseDecimal("1.1")])
+}
+
+func TestDecimal_Arithmetic(t *testing.T) {
+	a := MustParseDecimal("0.1")
+	b := MustParseDecimal("0.2")
+
+	assert.Equal(t, "0.3", a.Add(b).String())
+	assert.Equal(t, "-0.1", a.Sub(b).String())
+	assert.Equal(t, "0.02", a.Mul(b).String())
+	assert.Equal(t, "0.5", a.Div(b, 8).String())
+	assert.Equal(t, "0.33333333", DecimalFromInt(1).Div(DecimalFromInt(3), 8).String())
+	assert.Equal(t, "-0.66666667", DecimalFromInt(-2).Div(DecimalFromInt(3), 8).String())
+	assert.Equal(t, "1500", MustParseDecimal("0.015").Div(MustParseDecimal("0.00001"), 2).String())
+	assert.Equal(t, "-0.1", a.Neg().String())
+	assert.Equal(t, "0.1", a.Neg().Abs().String())
+
+	assert.Panics(t, func() { a.Div(Decimal{}, 8) })
+
+	// more precision than a float64 can hold
+	nano := MustParseDecimal("1.000000000000000000000000000001")
+	assert.Equal(t, "2.000000000000000000000000000002", nano.Add(nano).String())
+}
+
+func TestDecimal_Compare(t *testing.T) {
+	assert.Equal(t, -1, MustParseDecimal("0.09").Cmp(MustParseDecimal("0.1")))
+	assert.Equal(t, 0, MustParseDecimal("0.10").Cmp(MustParseDecimal("0.1")))
+	assert.Equal(t, 1, MustParseDecimal("-1").Cmp(MustParseDecimal("-2")))
+
+	assert.Equal(t, -1, MustParseDecimal("-3").Sign())
+	assert.Equal(t, 0, Decimal{}.Sign())
+	assert.Equal(t, 1, MustParseDecimal("3").Sign())
+	assert.True(t, Decimal{}.IsZero())
+	assert.Equal(t, 2, MustParseDecimal("1.250").Places())
+}
+
+func TestDecimal_Round(t *testing.T) {
+	testCases := []struct {
+		in     string
+		places int
+		round  string
+		down   string
+		up     string
+	}{
+		{in: "1.2345", places: 2, round: "1.23", down: "1.23", up: "1.24"},
+		{in: "1.235", places: 2, round: "1.24", down: "1.23", up: "1.24"},
+		{in: "-1.235", places: 2, round: "-1.24", down: "-1.23", up: "-1.24"},
+		{in: "1.2", places: 4, round: "1.2", down: "1.2", up: "1.2"},
+		{in: "0.004", places: 2, round: "0", down: "0", up: "0.01"},
+		{in: "1250", places: -2, round: "1300", down: "1200", up: "1300"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			d := MustParseDecimal(tc.in)
+			assert.Equal(t, tc.round, d.Round(tc.places).String())
+			assert.Equal(t, tc.down, d.RoundDown(tc.places).String())
+			assert.Equal(t, tc.up, d.RoundUp(tc.places).String())
+		})
+	}
+}
+
+func TestDecimal_StringFixed(t *testing.T) {
+	assert.Equal(t, "10.00000000", DecimalFromInt(10).StringFixed(8))
+	assert.Equal(t, "0.10", MustParseDecimal("0.1").StringFixed(2))
+	assert.Equal(t, "0.13", MustParseDecimal("0.125").StringFixed(2))
+	assert.Equal(t, "0.00", MustParseDecimal("-0.001").StringFixed(2))
+	assert.Equal(t, "12", MustParseDecimal("12.3").StringFixed(0))
+}
+
+func TestDecimal_Float(t *testing.T) {
+	assert.Equal(t, "0.1", DecimalFromFloat(0.1).String())
+	assert.Equal(t, "123456789", DecimalFromFloat(123456789).String())
+	assert.Equal(t, 0.00000033, MustParseDecimal("0.00000033").Float64())
+	assert.Panics(t, func() { DecimalFromFloat(math.Inf(1)) })
+}
+
+func TestDecimal_JSON(t *testing.T) {
+	var got struct {
+		A Decimal `json:"a"`
+		B Decimal `json:"b"`
+		C Decimal `json:"c"`
+		D Decimal `json:"d"`
+	}
+
+	err := json.Unmarshal([]byte(`{"a": "0.00000033", "b": 0.12891653720125376, "c": null, "d": ""}`), &got)
+	if assert.NoError(t, err) {
+		assert.Equal(t, MustParseDecimal("0.00000033"), got.A)
+		assert.Equal(t, "0.12891653720125376", got.B.String())
+		assert.True(t, got.C.IsZero())
+		assert.True(t, got.D.IsZero())
+	}
+
+	encoded, err := json.Marshal(got)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"a": "0.00000033", "b": "0.12891653720125376", "c": "0", "d": "0"}`, string(encoded))
+	}
+
+	assert.Error(t, json.Unmarshal([]byte(`{"a": "one"}`), &got))
+	assert.Error(t, json.Unmarshal([]byte(`{"a": true}`), &got))
+}
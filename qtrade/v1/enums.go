@@ -3,69 +3,64 @@
 
 package qtrade
 
+//go:generate go run ./internal/genenums -in internal/genenums/common.json -out enums_gen.go
+
 import (
+	"encoding/json"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 type Currency string
 
-const (
-	USDT  Currency = "USDT"
-	BAN   Currency = "BAN"
-	BTM   Currency = "BTM"
-	DOGE  Currency = "DOGE"
-	MCM   Currency = "MCM"
-	WEBD  Currency = "WEBD"
-	QUAN  Currency = "QUAN"
-	PEG   Currency = "PEG"
-	RVN   Currency = "RVN"
-	BAC   Currency = "BAC"
-	CCX   Currency = "CCX"
-	XEQ   Currency = "XEQ"
-	RUPX  Currency = "RUPX"
-	PHL   Currency = "PHL"
-	FCT   Currency = "FCT"
-	DGB   Currency = "DGB"
-	XBR   Currency = "XBR"
-	ETH   Currency = "ETH"
-	BTC   Currency = "BTC"
-	SCC   Currency = "SCC"
-	PUSD  Currency = "pUSD"
-	REDN  Currency = "REDN"
-	SNOW  Currency = "SNOW"
-	HTR   Currency = "HTR"
-	CPR   Currency = "CPR"
-	CRUZ  Currency = "CRUZ"
-	VEO   Currency = "VEO"
-	KLP   Currency = "KLP"
-	VLS   Currency = "VLS"
-	NANO  Currency = "NANO"
-	DEFT  Currency = "DEFT"
-	LTC   Currency = "LTC"
-	RCO   Currency = "RCO"
-	TAO1  Currency = "TAO1"
-	LUCK  Currency = "LUCK"
-	WFCT  Currency = "WFCT"
-	MMO   Currency = "MMO"
-	NYZO  Currency = "NYZO"
-	BWS20 Currency = "BWS20"
-	BWS10 Currency = "BWS10"
-	THC   Currency = "THC"
-	XTO   Currency = "XTO"
-	GRIN  Currency = "GRIN"
-	PASC  Currency = "PASC"
-	ARMS  Currency = "ARMS"
-	RTM   Currency = "RTM"
-	ZANO  Currency = "ZANO"
-	ANU   Currency = "ANU"
-	XCP   Currency = "XCP"
-	ARO   Currency = "ARO"
-	BIS   Currency = "BIS"
-	IDNA  Currency = "IDNA"
-	HLS   Currency = "HLS"
-	PFCT  Currency = "pFCT"
-)
+// ParseCurrency returns the currency with the given code, such as "BTC" or
+// "pUSD". The code is not case sensitive.
+func ParseCurrency(s string) (Currency, error) {
+	if currency, ok := parseCurrency(strings.ToUpper(s)); ok {
+		return currency, nil
+	}
+
+	return "", errors.Errorf("unknown currency %q", s)
+}
+
+func (c Currency) MarshalText() ([]byte, error) {
+	return []byte(c), nil
+}
+
+// UnmarshalText accepts the currencies known to ParseCurrency, and the empty
+// string.
+func (c *Currency) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*c = ""
+
+		return nil
+	}
+
+	currency, err := ParseCurrency(string(text))
+	if err != nil {
+		return err
+	}
+
+	*c = currency
+
+	return nil
+}
+
+// UnmarshalJSON accepts any currency code, because the API lists new
+// currencies before this package knows about them.
+func (c *Currency) UnmarshalJSON(data []byte) error {
+	var code string
+	if err := json.Unmarshal(data, &code); err != nil {
+		return err
+	}
+
+	*c = Currency(code)
+
+	return nil
+}
 
 type CurrencyStatus string
 
@@ -79,199 +74,109 @@ const (
 
 type Market int
 
-// nolint: golint
-const (
-	LTC_BTC     Market = 1
-	RCO_BTC     Market = 2
-	REDN_BTC    Market = 3
-	CPR_BTC     Market = 4
-	BAC_BTC     Market = 5
-	QUAN_BTC    Market = 6
-	RVN_BTC     Market = 7
-	MMO_BTC     Market = 8
-	BTM_BTC     Market = 9
-	ANU_BTC     Market = 10
-	BWS20_BTC   Market = 11
-	BWS20_BWS10 Market = 12
-	DEFT_BTC    Market = 13
-	RUPX_BTC    Market = 14
-	VEO_BTC     Market = 15
-	THC_BTC     Market = 16
-	SCC_BTC     Market = 17
-	XBR_BTC     Market = 18
-	SNOW_BTC    Market = 19
-	BIS_BTC     Market = 20
-	PHL_BTC     Market = 21
-	GRIN_BTC    Market = 23
-	NYZO_BTC    Market = 24
-	TAO1_BTC    Market = 25
-	XEQ_BTC     Market = 26
-	VLS_BTC     Market = 27
-	ZANO_BTC    Market = 28
-	PASC_BTC    Market = 30
-	NANO_BTC    Market = 31
-	CRUZ_BTC    Market = 32
-	BAN_BTC     Market = 33
-	MCM_BTC     Market = 34
-	ARO_BTC     Market = 35
-	DOGE_BTC    Market = 36
-	HLS_BTC     Market = 37
-	WEBD_BTC    Market = 38
-	ARMS_BTC    Market = 39
-	CCX_BTC     Market = 40
-	ETH_BTC     Market = 41
-	PEG_BTC     Market = 42
-	BTC_pUSD    Market = 43
-	ETH_pUSD    Market = 44
-	PEG_pUSD    Market = 45
-	PFCT_pUSD   Market = 46
-	FCT_pUSD    Market = 47
-	FCT_BTC     Market = 48
-	IDNA_BTC    Market = 49
-	DGB_BTC     Market = 50
-	KLP_BTC     Market = 51
-	XTO_BTC     Market = 52
-	LUCK_BTC    Market = 53
-	HTR_BTC     Market = 54
-	RTM_BTC     Market = 55
-	BTC_USDT    Market = 56
-	ETH_USDT    Market = 57
-	NYZO_USDT   Market = 58
-	KLP_USDT    Market = 59
-	HTR_USDT    Market = 60
-	WFCT_FCT    Market = 61
-)
+// ParseMarket returns the market with the given name, such as "LTC_BTC", or
+// numeric ID. Names are not case sensitive. Markets unknown to this package
+// are rejected; see Registry.ParseMarket for markets listed since, and
+// ParseMarketID to accept any ID.
+func ParseMarket(s string) (Market, error) {
+	if market, ok := parseMarket(strings.ToUpper(s)); ok {
+		return market, nil
+	}
 
-func (m Market) String() string {
-	switch m {
-	case LTC_BTC:
-		return "LTC_BTC"
-	case RCO_BTC:
-		return "RCO_BTC"
-	case REDN_BTC:
-		return "REDN_BTC"
-	case CPR_BTC:
-		return "CPR_BTC"
-	case BAC_BTC:
-		return "BAC_BTC"
-	case QUAN_BTC:
-		return "QUAN_BTC"
-	case RVN_BTC:
-		return "RVN_BTC"
-	case MMO_BTC:
-		return "MMO_BTC"
-	case BTM_BTC:
-		return "BTM_BTC"
-	case ANU_BTC:
-		return "ANU_BTC"
-	case BWS20_BTC:
-		return "BWS20_BTC"
-	case BWS20_BWS10:
-		return "BWS20_BWS10"
-	case DEFT_BTC:
-		return "DEFT_BTC"
-	case RUPX_BTC:
-		return "RUPX_BTC"
-	case VEO_BTC:
-		return "VEO_BTC"
-	case THC_BTC:
-		return "THC_BTC"
-	case SCC_BTC:
-		return "SCC_BTC"
-	case XBR_BTC:
-		return "XBR_BTC"
-	case SNOW_BTC:
-		return "SNOW_BTC"
-	case BIS_BTC:
-		return "BIS_BTC"
-	case PHL_BTC:
-		return "PHL_BTC"
-	case GRIN_BTC:
-		return "GRIN_BTC"
-	case NYZO_BTC:
-		return "NYZO_BTC"
-	case TAO1_BTC:
-		return "TAO1_BTC"
-	case XEQ_BTC:
-		return "XEQ_BTC"
-	case VLS_BTC:
-		return "VLS_BTC"
-	case ZANO_BTC:
-		return "ZANO_BTC"
-	case PASC_BTC:
-		return "PASC_BTC"
-	case NANO_BTC:
-		return "NANO_BTC"
-	case CRUZ_BTC:
-		return "CRUZ_BTC"
-	case BAN_BTC:
-		return "BAN_BTC"
-	case MCM_BTC:
-		return "MCM_BTC"
-	case ARO_BTC:
-		return "ARO_BTC"
-	case DOGE_BTC:
-		return "DOGE_BTC"
-	case HLS_BTC:
-		return "HLS_BTC"
-	case WEBD_BTC:
-		return "WEBD_BTC"
-	case ARMS_BTC:
-		return "ARMS_BTC"
-	case CCX_BTC:
-		return "CCX_BTC"
-	case ETH_BTC:
-		return "ETH_BTC"
-	case PEG_BTC:
-		return "PEG_BTC"
-	case BTC_pUSD:
-		return "BTC_pUSD"
-	case ETH_pUSD:
-		return "ETH_pUSD"
-	case PEG_pUSD:
-		return "PEG_pUSD"
-	case PFCT_pUSD:
-		return "pFCT_pUSD"
-	case FCT_pUSD:
-		return "FCT_pUSD"
-	case FCT_BTC:
-		return "FCT_BTC"
-	case IDNA_BTC:
-		return "IDNA_BTC"
-	case DGB_BTC:
-		return "DGB_BTC"
-	case KLP_BTC:
-		return "KLP_BTC"
-	case XTO_BTC:
-		return "XTO_BTC"
-	case LUCK_BTC:
-		return "LUCK_BTC"
-	case HTR_BTC:
-		return "HTR_BTC"
-	case RTM_BTC:
-		return "RTM_BTC"
-	case BTC_USDT:
-		return "BTC_USDT"
-	case ETH_USDT:
-		return "ETH_USDT"
-	case NYZO_USDT:
-		return "NYZO_USDT"
-	case KLP_USDT:
-		return "KLP_USDT"
-	case HTR_USDT:
-		return "HTR_USDT"
-	case WFCT_FCT:
-		return "WFCT_FCT"
+	if market, err := ParseMarketID(s); err == nil {
+		if _, _, ok := market.currencies(); ok {
+			return market, nil
+		}
 	}
-	return "unknown"
+
+	return 0, errors.Errorf("unknown market %q", s)
 }
 
+// ParseMarketID returns the market with the given numeric ID, whether or not
+// it is known to this package.
+func ParseMarketID(s string) (Market, error) {
+	id, err := strconv.Atoi(s)
+	if err != nil || id <= 0 {
+		return 0, errors.Errorf("invalid market ID %q", s)
+	}
+
+	return Market(id), nil
+}
+
+// MarketCurrency returns the currency traded in m, or "" if m is not known to
+// this package.
 func (m Market) MarketCurrency() Currency {
-	return Currency(strings.Split(m.String(), "_")[0])
+	currency, _, _ := m.currencies()
+
+	return currency
 }
 
+// BaseCurrency returns the currency that prices in m are quoted in, or "" if m
+// is not known to this package.
 func (m Market) BaseCurrency() Currency {
-	return Currency(strings.Split(m.String(), "_")[1])
+	_, currency, _ := m.currencies()
+
+	return currency
+}
+
+// MarshalText encodes m by name, or by ID if it is not known to this package.
+// The zero Market encodes as the empty string. Like Currency, markets unknown
+// to this package do not decode again with UnmarshalText.
+func (m Market) MarshalText() ([]byte, error) {
+	if m == 0 {
+		return []byte{}, nil
+	}
+
+	if _, _, ok := m.currencies(); ok {
+		return []byte(m.String()), nil
+	}
+
+	return []byte(strconv.Itoa(int(m))), nil
+}
+
+// UnmarshalText accepts the markets known to ParseMarket, and the empty
+// string.
+func (m *Market) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*m = 0
+
+		return nil
+	}
+
+	market, err := ParseMarket(string(text))
+	if err != nil {
+		return err
+	}
+
+	*m = market
+
+	return nil
+}
+
+// UnmarshalJSON accepts a market ID, as sent by the API, or a string accepted
+// by ParseMarket.
+func (m *Market) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] != '"' {
+		var id int
+		if err := json.Unmarshal(data, &id); err != nil {
+			return err
+		}
+
+		*m = Market(id)
+
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	return m.UnmarshalText([]byte(s))
 }
 
 type OrderType string
@@ -284,15 +189,49 @@ const (
 type Interval string
 
 const (
-	FiveMin    = "fivemin"
-	FifteenMin = "fifteenmin"
-	ThirtyMin  = "thirtymin"
-	OneHour    = "onehour"
-	TwoHour    = "twohour"
-	FourHour   = "fourhour"
-	OneDay     = "oneday"
+	FiveMin    Interval = "fivemin"
+	FifteenMin Interval = "fifteenmin"
+	ThirtyMin  Interval = "thirtymin"
+	OneHour    Interval = "onehour"
+	TwoHour    Interval = "twohour"
+	FourHour   Interval = "fourhour"
+	OneDay     Interval = "oneday"
 )
 
+// ParseInterval returns the interval with the given name, such as "fivemin".
+// The name is not case sensitive.
+func ParseInterval(s string) (Interval, error) {
+	interval := Interval(strings.ToLower(s))
+	if interval.Duration() == 0 {
+		return "", errors.Errorf("unknown interval %q", s)
+	}
+
+	return interval, nil
+}
+
+func (interval Interval) MarshalText() ([]byte, error) {
+	return []byte(interval), nil
+}
+
+// UnmarshalText accepts the intervals known to ParseInterval, and the empty
+// string.
+func (interval *Interval) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*interval = ""
+
+		return nil
+	}
+
+	parsed, err := ParseInterval(string(text))
+	if err != nil {
+		return err
+	}
+
+	*interval = parsed
+
+	return nil
+}
+
 func (interval Interval) Duration() time.Duration {
 	switch interval {
 	case FiveMin:
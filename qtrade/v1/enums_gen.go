@@ -0,0 +1,678 @@
+// Code generated by genenums from internal/genenums/common.json. DO NOT EDIT.
+
+//go:build !test
+// +build !test
+
+package qtrade
+
+const (
+	ANU   Currency = "ANU"
+	ARMS  Currency = "ARMS"
+	ARO   Currency = "ARO"
+	BAC   Currency = "BAC"
+	BAN   Currency = "BAN"
+	BIS   Currency = "BIS"
+	BTC   Currency = "BTC"
+	BTM   Currency = "BTM"
+	BWS10 Currency = "BWS10"
+	BWS20 Currency = "BWS20"
+	CCX   Currency = "CCX"
+	CPR   Currency = "CPR"
+	CRUZ  Currency = "CRUZ"
+	DEFT  Currency = "DEFT"
+	DGB   Currency = "DGB"
+	DOGE  Currency = "DOGE"
+	ETH   Currency = "ETH"
+	FCT   Currency = "FCT"
+	GRIN  Currency = "GRIN"
+	HLS   Currency = "HLS"
+	HTR   Currency = "HTR"
+	IDNA  Currency = "IDNA"
+	KLP   Currency = "KLP"
+	LTC   Currency = "LTC"
+	LUCK  Currency = "LUCK"
+	MCM   Currency = "MCM"
+	MMO   Currency = "MMO"
+	NANO  Currency = "NANO"
+	NYZO  Currency = "NYZO"
+	PASC  Currency = "PASC"
+	PEG   Currency = "PEG"
+	PFCT  Currency = "pFCT"
+	PHL   Currency = "PHL"
+	PUSD  Currency = "pUSD"
+	QUAN  Currency = "QUAN"
+	RCO   Currency = "RCO"
+	REDN  Currency = "REDN"
+	RTM   Currency = "RTM"
+	RUPX  Currency = "RUPX"
+	RVN   Currency = "RVN"
+	SCC   Currency = "SCC"
+	SNOW  Currency = "SNOW"
+	TAO1  Currency = "TAO1"
+	THC   Currency = "THC"
+	USDT  Currency = "USDT"
+	VEO   Currency = "VEO"
+	VLS   Currency = "VLS"
+	WEBD  Currency = "WEBD"
+	WFCT  Currency = "WFCT"
+	XBR   Currency = "XBR"
+	XCP   Currency = "XCP"
+	XEQ   Currency = "XEQ"
+	XTO   Currency = "XTO"
+	ZANO  Currency = "ZANO"
+)
+
+// parseCurrency returns the currency with the given code in upper case.
+func parseCurrency(code string) (Currency, bool) {
+	switch code {
+	case "ANU":
+		return ANU, true
+	case "ARMS":
+		return ARMS, true
+	case "ARO":
+		return ARO, true
+	case "BAC":
+		return BAC, true
+	case "BAN":
+		return BAN, true
+	case "BIS":
+		return BIS, true
+	case "BTC":
+		return BTC, true
+	case "BTM":
+		return BTM, true
+	case "BWS10":
+		return BWS10, true
+	case "BWS20":
+		return BWS20, true
+	case "CCX":
+		return CCX, true
+	case "CPR":
+		return CPR, true
+	case "CRUZ":
+		return CRUZ, true
+	case "DEFT":
+		return DEFT, true
+	case "DGB":
+		return DGB, true
+	case "DOGE":
+		return DOGE, true
+	case "ETH":
+		return ETH, true
+	case "FCT":
+		return FCT, true
+	case "GRIN":
+		return GRIN, true
+	case "HLS":
+		return HLS, true
+	case "HTR":
+		return HTR, true
+	case "IDNA":
+		return IDNA, true
+	case "KLP":
+		return KLP, true
+	case "LTC":
+		return LTC, true
+	case "LUCK":
+		return LUCK, true
+	case "MCM":
+		return MCM, true
+	case "MMO":
+		return MMO, true
+	case "NANO":
+		return NANO, true
+	case "NYZO":
+		return NYZO, true
+	case "PASC":
+		return PASC, true
+	case "PEG":
+		return PEG, true
+	case "PFCT":
+		return PFCT, true
+	case "PHL":
+		return PHL, true
+	case "PUSD":
+		return PUSD, true
+	case "QUAN":
+		return QUAN, true
+	case "RCO":
+		return RCO, true
+	case "REDN":
+		return REDN, true
+	case "RTM":
+		return RTM, true
+	case "RUPX":
+		return RUPX, true
+	case "RVN":
+		return RVN, true
+	case "SCC":
+		return SCC, true
+	case "SNOW":
+		return SNOW, true
+	case "TAO1":
+		return TAO1, true
+	case "THC":
+		return THC, true
+	case "USDT":
+		return USDT, true
+	case "VEO":
+		return VEO, true
+	case "VLS":
+		return VLS, true
+	case "WEBD":
+		return WEBD, true
+	case "WFCT":
+		return WFCT, true
+	case "XBR":
+		return XBR, true
+	case "XCP":
+		return XCP, true
+	case "XEQ":
+		return XEQ, true
+	case "XTO":
+		return XTO, true
+	case "ZANO":
+		return ZANO, true
+	}
+
+	return "", false
+}
+
+// CurrencyDecimalPlaces is the number of decimal places of each currency.
+var CurrencyDecimalPlaces = map[Currency]int{
+	ANU:   8,
+	ARMS:  8,
+	ARO:   8,
+	BAC:   8,
+	BAN:   29,
+	BIS:   8,
+	BTC:   8,
+	BTM:   8,
+	BWS10: 8,
+	BWS20: 8,
+	CCX:   6,
+	CPR:   8,
+	CRUZ:  8,
+	DEFT:  8,
+	DGB:   8,
+	DOGE:  8,
+	ETH:   18,
+	FCT:   8,
+	GRIN:  9,
+	HLS:   18,
+	HTR:   2,
+	IDNA:  18,
+	KLP:   12,
+	LTC:   8,
+	LUCK:  18,
+	MCM:   9,
+	MMO:   8,
+	NANO:  30,
+	NYZO:  6,
+	PASC:  4,
+	PEG:   8,
+	PFCT:  8,
+	PHL:   8,
+	PUSD:  8,
+	QUAN:  8,
+	RCO:   8,
+	REDN:  8,
+	RTM:   8,
+	RUPX:  8,
+	RVN:   8,
+	SCC:   8,
+	SNOW:  6,
+	TAO1:  8,
+	THC:   8,
+	USDT:  6,
+	VEO:   8,
+	VLS:   8,
+	WEBD:  4,
+	WFCT:  8,
+	XBR:   8,
+	XCP:   8,
+	XEQ:   4,
+	XTO:   18,
+	ZANO:  12,
+}
+
+// nolint: golint
+const (
+	LTC_BTC     Market = 1
+	RCO_BTC     Market = 2
+	REDN_BTC    Market = 3
+	CPR_BTC     Market = 4
+	BAC_BTC     Market = 5
+	QUAN_BTC    Market = 6
+	RVN_BTC     Market = 7
+	MMO_BTC     Market = 8
+	BTM_BTC     Market = 9
+	ANU_BTC     Market = 10
+	BWS20_BTC   Market = 11
+	BWS20_BWS10 Market = 12
+	DEFT_BTC    Market = 13
+	RUPX_BTC    Market = 14
+	VEO_BTC     Market = 15
+	THC_BTC     Market = 16
+	SCC_BTC     Market = 17
+	XBR_BTC     Market = 18
+	SNOW_BTC    Market = 19
+	BIS_BTC     Market = 20
+	PHL_BTC     Market = 21
+	GRIN_BTC    Market = 23
+	NYZO_BTC    Market = 24
+	TAO1_BTC    Market = 25
+	XEQ_BTC     Market = 26
+	VLS_BTC     Market = 27
+	ZANO_BTC    Market = 28
+	PASC_BTC    Market = 30
+	NANO_BTC    Market = 31
+	CRUZ_BTC    Market = 32
+	BAN_BTC     Market = 33
+	MCM_BTC     Market = 34
+	ARO_BTC     Market = 35
+	DOGE_BTC    Market = 36
+	HLS_BTC     Market = 37
+	WEBD_BTC    Market = 38
+	ARMS_BTC    Market = 39
+	CCX_BTC     Market = 40
+	ETH_BTC     Market = 41
+	PEG_BTC     Market = 42
+	BTC_pUSD    Market = 43
+	ETH_pUSD    Market = 44
+	PEG_pUSD    Market = 45
+	PFCT_pUSD   Market = 46
+	FCT_pUSD    Market = 47
+	FCT_BTC     Market = 48
+	IDNA_BTC    Market = 49
+	DGB_BTC     Market = 50
+	KLP_BTC     Market = 51
+	XTO_BTC     Market = 52
+	LUCK_BTC    Market = 53
+	HTR_BTC     Market = 54
+	RTM_BTC     Market = 55
+	BTC_USDT    Market = 56
+	ETH_USDT    Market = 57
+	NYZO_USDT   Market = 58
+	KLP_USDT    Market = 59
+	HTR_USDT    Market = 60
+	WFCT_FCT    Market = 61
+)
+
+func (m Market) String() string {
+	switch m {
+	case LTC_BTC:
+		return "LTC_BTC"
+	case RCO_BTC:
+		return "RCO_BTC"
+	case REDN_BTC:
+		return "REDN_BTC"
+	case CPR_BTC:
+		return "CPR_BTC"
+	case BAC_BTC:
+		return "BAC_BTC"
+	case QUAN_BTC:
+		return "QUAN_BTC"
+	case RVN_BTC:
+		return "RVN_BTC"
+	case MMO_BTC:
+		return "MMO_BTC"
+	case BTM_BTC:
+		return "BTM_BTC"
+	case ANU_BTC:
+		return "ANU_BTC"
+	case BWS20_BTC:
+		return "BWS20_BTC"
+	case BWS20_BWS10:
+		return "BWS20_BWS10"
+	case DEFT_BTC:
+		return "DEFT_BTC"
+	case RUPX_BTC:
+		return "RUPX_BTC"
+	case VEO_BTC:
+		return "VEO_BTC"
+	case THC_BTC:
+		return "THC_BTC"
+	case SCC_BTC:
+		return "SCC_BTC"
+	case XBR_BTC:
+		return "XBR_BTC"
+	case SNOW_BTC:
+		return "SNOW_BTC"
+	case BIS_BTC:
+		return "BIS_BTC"
+	case PHL_BTC:
+		return "PHL_BTC"
+	case GRIN_BTC:
+		return "GRIN_BTC"
+	case NYZO_BTC:
+		return "NYZO_BTC"
+	case TAO1_BTC:
+		return "TAO1_BTC"
+	case XEQ_BTC:
+		return "XEQ_BTC"
+	case VLS_BTC:
+		return "VLS_BTC"
+	case ZANO_BTC:
+		return "ZANO_BTC"
+	case PASC_BTC:
+		return "PASC_BTC"
+	case NANO_BTC:
+		return "NANO_BTC"
+	case CRUZ_BTC:
+		return "CRUZ_BTC"
+	case BAN_BTC:
+		return "BAN_BTC"
+	case MCM_BTC:
+		return "MCM_BTC"
+	case ARO_BTC:
+		return "ARO_BTC"
+	case DOGE_BTC:
+		return "DOGE_BTC"
+	case HLS_BTC:
+		return "HLS_BTC"
+	case WEBD_BTC:
+		return "WEBD_BTC"
+	case ARMS_BTC:
+		return "ARMS_BTC"
+	case CCX_BTC:
+		return "CCX_BTC"
+	case ETH_BTC:
+		return "ETH_BTC"
+	case PEG_BTC:
+		return "PEG_BTC"
+	case BTC_pUSD:
+		return "BTC_pUSD"
+	case ETH_pUSD:
+		return "ETH_pUSD"
+	case PEG_pUSD:
+		return "PEG_pUSD"
+	case PFCT_pUSD:
+		return "pFCT_pUSD"
+	case FCT_pUSD:
+		return "FCT_pUSD"
+	case FCT_BTC:
+		return "FCT_BTC"
+	case IDNA_BTC:
+		return "IDNA_BTC"
+	case DGB_BTC:
+		return "DGB_BTC"
+	case KLP_BTC:
+		return "KLP_BTC"
+	case XTO_BTC:
+		return "XTO_BTC"
+	case LUCK_BTC:
+		return "LUCK_BTC"
+	case HTR_BTC:
+		return "HTR_BTC"
+	case RTM_BTC:
+		return "RTM_BTC"
+	case BTC_USDT:
+		return "BTC_USDT"
+	case ETH_USDT:
+		return "ETH_USDT"
+	case NYZO_USDT:
+		return "NYZO_USDT"
+	case KLP_USDT:
+		return "KLP_USDT"
+	case HTR_USDT:
+		return "HTR_USDT"
+	case WFCT_FCT:
+		return "WFCT_FCT"
+	}
+
+	return "unknown"
+}
+
+// parseMarket returns the market with the given name in upper case, such as
+// "BTC_PUSD".
+func parseMarket(name string) (Market, bool) {
+	switch name {
+	case "LTC_BTC":
+		return LTC_BTC, true
+	case "RCO_BTC":
+		return RCO_BTC, true
+	case "REDN_BTC":
+		return REDN_BTC, true
+	case "CPR_BTC":
+		return CPR_BTC, true
+	case "BAC_BTC":
+		return BAC_BTC, true
+	case "QUAN_BTC":
+		return QUAN_BTC, true
+	case "RVN_BTC":
+		return RVN_BTC, true
+	case "MMO_BTC":
+		return MMO_BTC, true
+	case "BTM_BTC":
+		return BTM_BTC, true
+	case "ANU_BTC":
+		return ANU_BTC, true
+	case "BWS20_BTC":
+		return BWS20_BTC, true
+	case "BWS20_BWS10":
+		return BWS20_BWS10, true
+	case "DEFT_BTC":
+		return DEFT_BTC, true
+	case "RUPX_BTC":
+		return RUPX_BTC, true
+	case "VEO_BTC":
+		return VEO_BTC, true
+	case "THC_BTC":
+		return THC_BTC, true
+	case "SCC_BTC":
+		return SCC_BTC, true
+	case "XBR_BTC":
+		return XBR_BTC, true
+	case "SNOW_BTC":
+		return SNOW_BTC, true
+	case "BIS_BTC":
+		return BIS_BTC, true
+	case "PHL_BTC":
+		return PHL_BTC, true
+	case "GRIN_BTC":
+		return GRIN_BTC, true
+	case "NYZO_BTC":
+		return NYZO_BTC, true
+	case "TAO1_BTC":
+		return TAO1_BTC, true
+	case "XEQ_BTC":
+		return XEQ_BTC, true
+	case "VLS_BTC":
+		return VLS_BTC, true
+	case "ZANO_BTC":
+		return ZANO_BTC, true
+	case "PASC_BTC":
+		return PASC_BTC, true
+	case "NANO_BTC":
+		return NANO_BTC, true
+	case "CRUZ_BTC":
+		return CRUZ_BTC, true
+	case "BAN_BTC":
+		return BAN_BTC, true
+	case "MCM_BTC":
+		return MCM_BTC, true
+	case "ARO_BTC":
+		return ARO_BTC, true
+	case "DOGE_BTC":
+		return DOGE_BTC, true
+	case "HLS_BTC":
+		return HLS_BTC, true
+	case "WEBD_BTC":
+		return WEBD_BTC, true
+	case "ARMS_BTC":
+		return ARMS_BTC, true
+	case "CCX_BTC":
+		return CCX_BTC, true
+	case "ETH_BTC":
+		return ETH_BTC, true
+	case "PEG_BTC":
+		return PEG_BTC, true
+	case "BTC_PUSD":
+		return BTC_pUSD, true
+	case "ETH_PUSD":
+		return ETH_pUSD, true
+	case "PEG_PUSD":
+		return PEG_pUSD, true
+	case "PFCT_PUSD":
+		return PFCT_pUSD, true
+	case "FCT_PUSD":
+		return FCT_pUSD, true
+	case "FCT_BTC":
+		return FCT_BTC, true
+	case "IDNA_BTC":
+		return IDNA_BTC, true
+	case "DGB_BTC":
+		return DGB_BTC, true
+	case "KLP_BTC":
+		return KLP_BTC, true
+	case "XTO_BTC":
+		return XTO_BTC, true
+	case "LUCK_BTC":
+		return LUCK_BTC, true
+	case "HTR_BTC":
+		return HTR_BTC, true
+	case "RTM_BTC":
+		return RTM_BTC, true
+	case "BTC_USDT":
+		return BTC_USDT, true
+	case "ETH_USDT":
+		return ETH_USDT, true
+	case "NYZO_USDT":
+		return NYZO_USDT, true
+	case "KLP_USDT":
+		return KLP_USDT, true
+	case "HTR_USDT":
+		return HTR_USDT, true
+	case "WFCT_FCT":
+		return WFCT_FCT, true
+	}
+
+	return 0, false
+}
+
+// currencies returns the market and base currencies of m.
+func (m Market) currencies() (Currency, Currency, bool) {
+	switch m {
+	case LTC_BTC:
+		return LTC, BTC, true
+	case RCO_BTC:
+		return RCO, BTC, true
+	case REDN_BTC:
+		return REDN, BTC, true
+	case CPR_BTC:
+		return CPR, BTC, true
+	case BAC_BTC:
+		return BAC, BTC, true
+	case QUAN_BTC:
+		return QUAN, BTC, true
+	case RVN_BTC:
+		return RVN, BTC, true
+	case MMO_BTC:
+		return MMO, BTC, true
+	case BTM_BTC:
+		return BTM, BTC, true
+	case ANU_BTC:
+		return ANU, BTC, true
+	case BWS20_BTC:
+		return BWS20, BTC, true
+	case BWS20_BWS10:
+		return BWS20, BWS10, true
+	case DEFT_BTC:
+		return DEFT, BTC, true
+	case RUPX_BTC:
+		return RUPX, BTC, true
+	case VEO_BTC:
+		return VEO, BTC, true
+	case THC_BTC:
+		return THC, BTC, true
+	case SCC_BTC:
+		return SCC, BTC, true
+	case XBR_BTC:
+		return XBR, BTC, true
+	case SNOW_BTC:
+		return SNOW, BTC, true
+	case BIS_BTC:
+		return BIS, BTC, true
+	case PHL_BTC:
+		return PHL, BTC, true
+	case GRIN_BTC:
+		return GRIN, BTC, true
+	case NYZO_BTC:
+		return NYZO, BTC, true
+	case TAO1_BTC:
+		return TAO1, BTC, true
+	case XEQ_BTC:
+		return XEQ, BTC, true
+	case VLS_BTC:
+		return VLS, BTC, true
+	case ZANO_BTC:
+		return ZANO, BTC, true
+	case PASC_BTC:
+		return PASC, BTC, true
+	case NANO_BTC:
+		return NANO, BTC, true
+	case CRUZ_BTC:
+		return CRUZ, BTC, true
+	case BAN_BTC:
+		return BAN, BTC, true
+	case MCM_BTC:
+		return MCM, BTC, true
+	case ARO_BTC:
+		return ARO, BTC, true
+	case DOGE_BTC:
+		return DOGE, BTC, true
+	case HLS_BTC:
+		return HLS, BTC, true
+	case WEBD_BTC:
+		return WEBD, BTC, true
+	case ARMS_BTC:
+		return ARMS, BTC, true
+	case CCX_BTC:
+		return CCX, BTC, true
+	case ETH_BTC:
+		return ETH, BTC, true
+	case PEG_BTC:
+		return PEG, BTC, true
+	case BTC_pUSD:
+		return BTC, PUSD, true
+	case ETH_pUSD:
+		return ETH, PUSD, true
+	case PEG_pUSD:
+		return PEG, PUSD, true
+	case PFCT_pUSD:
+		return PFCT, PUSD, true
+	case FCT_pUSD:
+		return FCT, PUSD, true
+	case FCT_BTC:
+		return FCT, BTC, true
+	case IDNA_BTC:
+		return IDNA, BTC, true
+	case DGB_BTC:
+		return DGB, BTC, true
+	case KLP_BTC:
+		return KLP, BTC, true
+	case XTO_BTC:
+		return XTO, BTC, true
+	case LUCK_BTC:
+		return LUCK, BTC, true
+	case HTR_BTC:
+		return HTR, BTC, true
+	case RTM_BTC:
+		return RTM, BTC, true
+	case BTC_USDT:
+		return BTC, USDT, true
+	case ETH_USDT:
+		return ETH, USDT, true
+	case NYZO_USDT:
+		return NYZO, USDT, true
+	case KLP_USDT:
+		return KLP, USDT, true
+	case HTR_USDT:
+		return HTR, USDT, true
+	case WFCT_FCT:
+		return WFCT, FCT, true
+	}
+
+	return "", "", false
+}
@@ -0,0 +1,211 @@
+package qtrade
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMarket(t *testing.T) {
+	testCases := []struct {
+		in      string
+		want    Market
+		wantErr bool
+	}{
+		{in: "LTC_BTC", want: LTC_BTC},
+		{in: "ltc_btc", want: LTC_BTC},
+		{in: "pFCT_pUSD", want: PFCT_pUSD},
+		{in: "BTC_PUSD", want: BTC_pUSD},
+		{in: "1", want: LTC_BTC},
+		{in: "999", wantErr: true},
+		{in: "unknown", wantErr: true},
+		{in: "BTC_LTC", wantErr: true},
+		{in: "0", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := ParseMarket(tc.in)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestParseMarketID(t *testing.T) {
+	got, err := ParseMarketID("999")
+	if assert.NoError(t, err) {
+		assert.Equal(t, Market(999), got)
+	}
+
+	_, err = ParseMarketID("LTC_BTC")
+	assert.EqualError(t, err, `invalid market ID "LTC_BTC"`)
+
+	_, err = ParseMarketID("0")
+	assert.Error(t, err)
+}
+
+func TestParseCurrency(t *testing.T) {
+	got, err := ParseCurrency("btc")
+	if assert.NoError(t, err) {
+		assert.Equal(t, BTC, got)
+	}
+
+	got, err = ParseCurrency("PUSD")
+	if assert.NoError(t, err) {
+		assert.Equal(t, PUSD, got)
+	}
+
+	_, err = ParseCurrency("XYZ")
+	assert.EqualError(t, err, `unknown currency "XYZ"`)
+}
+
+func TestParseInterval(t *testing.T) {
+	got, err := ParseInterval("FiveMin")
+	if assert.NoError(t, err) {
+		assert.Equal(t, FiveMin, got)
+	}
+
+	_, err = ParseInterval("fortnight")
+	assert.EqualError(t, err, `unknown interval "fortnight"`)
+}
+
+func TestMarket_Currencies(t *testing.T) {
+	assert.Equal(t, PFCT, PFCT_pUSD.MarketCurrency())
+	assert.Equal(t, PUSD, PFCT_pUSD.BaseCurrency())
+
+	assert.Equal(t, Currency(""), Market(999).MarketCurrency())
+	assert.Equal(t, Currency(""), Market(999).BaseCurrency())
+}
+
+func TestEnums_JSON(t *testing.T) {
+	type config struct {
+		Market   Market   `json:"market"`
+		Currency Currency `json:"currency"`
+		Interval Interval `json:"interval"`
+	}
+
+	encoded, err := json.Marshal(config{Market: BTC_pUSD, Currency: PUSD, Interval: OneHour})
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"market": "BTC_pUSD", "currency": "pUSD", "interval": "onehour"}`, string(encoded))
+	}
+
+	var got config
+	if assert.NoError(t, json.Unmarshal(encoded, &got)) {
+		assert.Equal(t, config{Market: BTC_pUSD, Currency: PUSD, Interval: OneHour}, got)
+	}
+
+	// markets unknown to the package encode by ID, but are not accepted back
+	encoded, err = json.Marshal(config{Market: Market(999)})
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(encoded), `"market":"999"`)
+	}
+
+	assert.Error(t, json.Unmarshal(encoded, &got))
+
+	// API payloads use numeric IDs and may list currencies that are not known yet
+	if assert.NoError(t, json.Unmarshal([]byte(`{"market": 1, "currency": "NEW"}`), &got)) {
+		assert.Equal(t, LTC_BTC, got.Market)
+		assert.Equal(t, Currency("NEW"), got.Currency)
+	}
+
+	assert.Error(t, json.Unmarshal([]byte(`{"market": "NOPE_BTC"}`), &got))
+	assert.Error(t, json.Unmarshal([]byte(`{"interval": "fortnight"}`), &got))
+
+	byMarket := map[Market]int{}
+	if assert.NoError(t, json.Unmarshal([]byte(`{"LTC_BTC": 3}`), &byMarket)) {
+		assert.Equal(t, map[Market]int{LTC_BTC: 3}, byMarket)
+	}
+}
+
+func TestEnums_Text(t *testing.T) {
+	var (
+		market   Market
+		currency Currency
+		interval Interval
+	)
+
+	// as used by flag, YAML and environment variable decoders
+	unmarshalers := map[string]encoding.TextUnmarshaler{
+		"eth_btc": &market,
+		"eth":     &currency,
+		"oneday":  &interval,
+	}
+
+	for text, unmarshaler := range unmarshalers {
+		assert.NoError(t, unmarshaler.UnmarshalText([]byte(text)))
+	}
+
+	assert.Equal(t, ETH_BTC, market)
+	assert.Equal(t, ETH, currency)
+	assert.Equal(t, OneDay, interval)
+
+	assert.Error(t, currency.UnmarshalText([]byte("NEW")))
+
+	text, err := market.MarshalText()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "ETH_BTC", string(text))
+	}
+}
+
+func TestAPITypes_JSON(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{
+			name:  "order",
+			value: Order{ID: 5, Market: LTC_BTC, Trades: []PrivateTrade{{ID: 7, Market: LTC_BTC}}},
+			want:  `"market_id":1`,
+		},
+		{
+			name:  "private trade",
+			value: PrivateTrade{ID: 7, Market: Market(999)},
+			want:  `"market_id":999`,
+		},
+		{
+			name:  "ticker",
+			value: Ticker{Market: LTC_BTC, IDHr: "LTC_BTC"},
+			want:  `"id":1`,
+		},
+		{
+			name:  "market",
+			value: MarketData{ID: LTC_BTC},
+			want:  `"id":1`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded, err := json.Marshal(tc.value)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Contains(t, string(encoded), tc.want)
+			assert.NotContains(t, string(encoded), `"market_id":"`)
+			assert.NotContains(t, string(encoded), `"id":"`)
+
+			decoded := reflect.New(reflect.TypeOf(tc.value))
+			if assert.NoError(t, json.Unmarshal(encoded, decoded.Interface())) {
+				assert.Equal(t, tc.value, decoded.Elem().Interface())
+			}
+		})
+	}
+
+	encoded, err := json.Marshal(PrivateTrade{ID: 7})
+	if assert.NoError(t, err) {
+		assert.NotContains(t, string(encoded), "market_id")
+	}
+}
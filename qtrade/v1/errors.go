@@ -0,0 +1,136 @@
+package qtrade
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrTooManyRequests = errors.New("too many requests")
+	ErrHTTPRetryable   = errors.New("a retryable HTTP error occurred")
+
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrOrderNotFound     = errors.New("order not found")
+	ErrOrderClosed       = errors.New("order already closed")
+	ErrUnauthorized      = errors.New("unauthorized")
+
+	// ErrInvalidOrder is returned by PlaceOrder for orders rejected before
+	// being sent.
+	ErrInvalidOrder = errors.New("invalid order")
+
+	// ErrInvalidWithdrawal is returned by Withdraw for amounts rejected before
+	// being sent.
+	ErrInvalidWithdrawal = errors.New("invalid withdrawal")
+
+	// ErrNoCredentials is returned by private endpoints of a client created with NewPublicClient.
+	ErrNoCredentials = errors.New("no API credentials configured")
+)
+
+// Error codes returned by the qTrade API in Error.Code.
+const (
+	CodeInvalidAuth       = "invalid_auth"
+	CodeInsufficientFunds = "insuff_funds"
+	CodeNotFound          = "not_found"
+	CodeOrderClosed       = "order_closed"
+	CodeOrderNotFound     = "order_not_found"
+	CodeTooManyRequests   = "too_many_requests"
+)
+
+// APIError is returned for every response with an HTTP error status. It keeps
+// the raw response body and the decoded errors, so callers can branch on
+// Error.Code using errors.As, or on the sentinel errors above using errors.Is.
+type APIError struct {
+	StatusCode int
+	Status     string
+	Body       []byte
+	Errors     []Error
+}
+
+func (err *APIError) Error() string {
+	msg := "API response: " + err.Status
+
+	if len(err.Errors) == 0 {
+		if len(err.Body) == 0 {
+			return msg
+		}
+
+		return msg + ": " + string(err.Body)
+	}
+
+	titles := make([]string, len(err.Errors))
+	for i, thisErr := range err.Errors {
+		titles[i] = thisErr.Error()
+	}
+
+	return msg + ": " + strings.Join(titles, "; ")
+}
+
+// HasCode reports whether any of the decoded errors has the given code.
+func (err *APIError) HasCode(code string) bool {
+	for _, thisErr := range err.Errors {
+		if thisErr.Code == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Is maps the HTTP status and error codes onto the sentinel errors of this package.
+func (err *APIError) Is(target error) bool {
+	switch target {
+	case ErrTooManyRequests:
+		return err.StatusCode == http.StatusTooManyRequests || err.HasCode(CodeTooManyRequests)
+	case ErrHTTPRetryable:
+		switch err.StatusCode {
+		case http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	case ErrInsufficientFunds:
+		return err.HasCode(CodeInsufficientFunds)
+	case ErrOrderNotFound:
+		// a plain 404 or not_found may be about a path, market or withdrawal
+		return err.HasCode(CodeOrderNotFound)
+	case ErrOrderClosed:
+		return err.HasCode(CodeOrderClosed)
+	case ErrUnauthorized:
+		return err.HasCode(CodeInvalidAuth) || err.StatusCode == http.StatusUnauthorized || err.StatusCode == http.StatusForbidden
+	}
+
+	return false
+}
+
+// IsInsufficientFunds reports whether err was caused by a lack of funds for an order or withdrawal.
+func IsInsufficientFunds(err error) bool {
+	return errors.Is(err, ErrInsufficientFunds)
+}
+
+// IsOrderNotFound reports whether err was caused by a request for an order that does not exist.
+func IsOrderNotFound(err error) bool {
+	return errors.Is(err, ErrOrderNotFound)
+}
+
+// IsOrderClosed reports whether err was caused by cancelling an order that was
+// already filled or canceled.
+func IsOrderClosed(err error) bool {
+	return errors.Is(err, ErrOrderClosed)
+}
+
+// IsInvalidOrder reports whether err was caused by an order that failed
+// validation before being sent.
+func IsInvalidOrder(err error) bool {
+	return errors.Is(err, ErrInvalidOrder)
+}
+
+// IsUnauthorized reports whether err was caused by missing or invalid credentials.
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsTooManyRequests reports whether err was caused by the API rate limit.
+func IsTooManyRequests(err error) bool {
+	return errors.Is(err, ErrTooManyRequests)
+}
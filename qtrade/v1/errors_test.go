@@ -0,0 +1,116 @@
+package qtrade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError_Is(t *testing.T) {
+	testCases := []struct {
+		name        string
+		err         *APIError
+		wantFunds   bool
+		wantMissing bool
+		wantClosed  bool
+		wantAuth    bool
+		wantLimited bool
+	}{
+		{
+			name: "insufficient funds",
+			err: &APIError{
+				StatusCode: 400,
+				Status:     "400 Bad Request",
+				Errors:     []Error{{Code: CodeInsufficientFunds, Title: "Insufficient funds"}},
+			},
+			wantFunds: true,
+		},
+		{
+			name: "order not found by code",
+			err: &APIError{
+				StatusCode: 400,
+				Status:     "400 Bad Request",
+				Errors:     []Error{{Code: CodeOrderNotFound, Title: "Order not found"}},
+			},
+			wantMissing: true,
+		},
+		{
+			name: "not found by status",
+			err: &APIError{
+				StatusCode: 404,
+				Status:     "404 Not Found",
+			},
+		},
+		{
+			name: "not found by code",
+			err: &APIError{
+				StatusCode: 404,
+				Status:     "404 Not Found",
+				Errors:     []Error{{Code: CodeNotFound, Title: "Not found"}},
+			},
+		},
+		{
+			name: "order closed",
+			err: &APIError{
+				StatusCode: 400,
+				Status:     "400 Bad Request",
+				Errors:     []Error{{Code: CodeOrderClosed, Title: "Order 109 is already closed"}},
+			},
+			wantClosed: true,
+		},
+		{
+			name: "invalid auth",
+			err: &APIError{
+				StatusCode: 403,
+				Status:     "403 Forbidden",
+				Errors:     []Error{{Code: CodeInvalidAuth, Title: "Invalid HMAC signature"}},
+			},
+			wantAuth: true,
+		},
+		{
+			name: "rate limited",
+			err: &APIError{
+				StatusCode: 429,
+				Status:     "429 Too Many Requests",
+			},
+			wantLimited: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			wrapped := errors.Wrap(tc.err, "failed to do the thing")
+
+			assert.Equal(t, tc.wantFunds, IsInsufficientFunds(wrapped))
+			assert.Equal(t, tc.wantMissing, IsOrderNotFound(wrapped))
+			assert.Equal(t, tc.wantClosed, IsOrderClosed(wrapped))
+			assert.Equal(t, tc.wantAuth, IsUnauthorized(wrapped))
+			assert.Equal(t, tc.wantLimited, IsTooManyRequests(wrapped))
+		})
+	}
+}
+
+func TestAPIError_As(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	registerTradableMarket()
+
+	httpmock.RegisterResponder("POST", "http://localhost/v1/user/buy_limit",
+		httpmock.NewStringResponder(400, `{"errors": [{"code": "insuff_funds","title": "Insufficient funds"},{"code": "invalid_market","title": "Invalid market"}]}`))
+
+	_, err := testClient.CreateBuyLimit(context.Background(), DecimalFromInt(10), LTC_BTC, MustParseDecimal("0.1"))
+
+	var apiErr *APIError
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, 400, apiErr.StatusCode)
+		assert.True(t, apiErr.HasCode("invalid_market"))
+		assert.Equal(t, "API response: 400: Insufficient funds; Invalid market", apiErr.Error())
+	}
+
+	assert.True(t, IsInsufficientFunds(err))
+	assert.False(t, IsUnauthorized(err))
+}
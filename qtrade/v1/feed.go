@@ -0,0 +1,361 @@
+package qtrade
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MarketEventKind is a set of the kinds of MarketEvent.
+type MarketEventKind int
+
+// The kinds of MarketEvent, which may be combined with |.
+const (
+	TickerEvent MarketEventKind = 1 << iota
+	TradeEvent
+	OrderbookEvent
+
+	AllMarketEvents = TickerEvent | TradeEvent | OrderbookEvent
+)
+
+// MarketEvent is published by a MarketDataFeed. Exactly one of Ticker, Trade
+// and Orderbook is set, according to Kind.
+type MarketEvent struct {
+	Kind      MarketEventKind
+	Market    Market
+	Ticker    *Ticker
+	Trade     *PublicTrade
+	Orderbook *OrderbookUpdate
+}
+
+// MarketEventFilter selects the events delivered to a subscription. The zero
+// value selects every event.
+type MarketEventFilter struct {
+	// Markets, if not empty, restricts the events to these markets.
+	Markets []Market
+	// Kinds, if not zero, restricts the events to these kinds.
+	Kinds MarketEventKind
+}
+
+func (filter MarketEventFilter) match(event MarketEvent) bool {
+	if filter.Kinds != 0 && filter.Kinds&event.Kind == 0 {
+		return false
+	}
+
+	if len(filter.Markets) == 0 {
+		return true
+	}
+
+	for _, market := range filter.Markets {
+		if market == event.Market {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Subscription receives the events of a MarketDataFeed that match its filter,
+// either on C or through a callback.
+type Subscription struct {
+	// C receives the events of subscriptions made with Subscribe. It is
+	// closed by Unsubscribe, and when the feed stops.
+	C <-chan MarketEvent
+
+	feed   *MarketDataFeed
+	filter MarketEventFilter
+	fn     func(MarketEvent)
+
+	mu      sync.Mutex
+	ch      chan MarketEvent
+	closed  bool
+	dropped int
+}
+
+// Unsubscribe stops the delivery of events. A callback may still be running
+// when it returns. It may be called more than once.
+func (sub *Subscription) Unsubscribe() {
+	sub.feed.mu.Lock()
+	delete(sub.feed.subs, sub)
+	sub.feed.mu.Unlock()
+
+	sub.close()
+}
+
+// Dropped returns how many events were discarded because C was full.
+func (sub *Subscription) Dropped() int {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	return sub.dropped
+}
+
+func (sub *Subscription) deliver(event MarketEvent) {
+	sub.mu.Lock()
+
+	if sub.closed {
+		sub.mu.Unlock()
+		return
+	}
+
+	if sub.fn != nil {
+		// the callback may unsubscribe, so it is called without the lock
+		sub.mu.Unlock()
+		sub.fn(event)
+
+		return
+	}
+
+	defer sub.mu.Unlock()
+
+	select {
+	case sub.ch <- event:
+	default:
+		sub.dropped++
+	}
+}
+
+func (sub *Subscription) close() {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	if !sub.closed && sub.ch != nil {
+		close(sub.ch)
+	}
+
+	sub.closed = true
+}
+
+// MarketDataFeed polls tickers, public trades and orderbooks of a set of
+// markets, and publishes their changes as events to its subscribers.
+//
+// The intervals may be changed before Run is called. Subscriptions may be
+// made and cancelled at any time.
+type MarketDataFeed struct {
+	// TickerInterval, TradesInterval and OrderbookInterval set how often
+	// tickers, trades and orderbooks are polled. A zero interval disables
+	// polling them.
+	TickerInterval    time.Duration
+	TradesInterval    time.Duration
+	OrderbookInterval time.Duration
+
+	// OnError, if set, is called with the errors of failed polls. It may be
+	// called from several goroutines at once.
+	OnError func(error)
+
+	api     PublicAPI
+	markets []Market
+	events  chan MarketEvent
+
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+// NewMarketDataFeed returns a feed of the given markets, which polls every
+// five seconds by default.
+func NewMarketDataFeed(api PublicAPI, markets ...Market) *MarketDataFeed {
+	return &MarketDataFeed{
+		TickerInterval:    time.Second * 5,
+		TradesInterval:    time.Second * 5,
+		OrderbookInterval: time.Second * 5,
+		api:               api,
+		markets:           markets,
+		events:            make(chan MarketEvent, 64),
+		subs:              map[*Subscription]struct{}{},
+	}
+}
+
+// Subscribe returns a subscription whose events are sent on a channel with
+// room for buffer events. Events that arrive while the channel is full are
+// dropped and counted, so that a slow subscriber does not hold up the others.
+func (feed *MarketDataFeed) Subscribe(filter MarketEventFilter, buffer int) *Subscription {
+	ch := make(chan MarketEvent, buffer)
+
+	return feed.subscribe(&Subscription{C: ch, ch: ch, filter: filter})
+}
+
+// SubscribeFunc returns a subscription that calls fn with its events, one at a
+// time. A slow callback delays the events of every subscriber, and eventually
+// the polls themselves.
+func (feed *MarketDataFeed) SubscribeFunc(filter MarketEventFilter, fn func(MarketEvent)) *Subscription {
+	return feed.subscribe(&Subscription{filter: filter, fn: fn})
+}
+
+func (feed *MarketDataFeed) subscribe(sub *Subscription) *Subscription {
+	sub.feed = feed
+
+	feed.mu.Lock()
+	feed.subs[sub] = struct{}{}
+	feed.mu.Unlock()
+
+	return sub
+}
+
+// Run polls until ctx is done, then closes the channels of all subscriptions
+// and returns the context's error. It may only be called once.
+func (feed *MarketDataFeed) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+
+	poll := func(interval time.Duration, fn func(context.Context)) {
+		if interval <= 0 {
+			return
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			for {
+				fn(ctx)
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+	}
+
+	poll(feed.TickerInterval, feed.tickerPoller())
+
+	for _, market := range feed.markets {
+		poll(feed.TradesInterval, feed.tradesPoller(market))
+		poll(feed.OrderbookInterval, feed.orderbookPoller(market))
+	}
+
+	go func() {
+		wg.Wait()
+		close(feed.events)
+	}()
+
+	for event := range feed.events {
+		feed.dispatch(event)
+	}
+
+	feed.mu.Lock()
+	subs := feed.subs
+	feed.subs = map[*Subscription]struct{}{}
+	feed.mu.Unlock()
+
+	for sub := range subs {
+		sub.close()
+	}
+
+	return ctx.Err()
+}
+
+func (feed *MarketDataFeed) dispatch(event MarketEvent) {
+	feed.mu.Lock()
+	subs := make([]*Subscription, 0, len(feed.subs))
+
+	for sub := range feed.subs {
+		if sub.filter.match(event) {
+			subs = append(subs, sub)
+		}
+	}
+	feed.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.deliver(event)
+	}
+}
+
+// publish queues event for dispatch, waiting while the queue is full.
+func (feed *MarketDataFeed) publish(ctx context.Context, event MarketEvent) {
+	select {
+	case feed.events <- event:
+	case <-ctx.Done():
+	}
+}
+
+func (feed *MarketDataFeed) reportError(ctx context.Context, err error) {
+	if feed.OnError != nil && ctx.Err() == nil {
+		feed.OnError(err)
+	}
+}
+
+// tickerPoller publishes the tickers of the feed's markets that changed since
+// the previous poll.
+func (feed *MarketDataFeed) tickerPoller() func(context.Context) {
+	watched := make(map[Market]bool, len(feed.markets))
+	for _, market := range feed.markets {
+		watched[market] = true
+	}
+
+	last := map[Market]Ticker{}
+
+	return func(ctx context.Context) {
+		tickers, err := feed.api.GetTickers(ctx)
+		if err != nil {
+			feed.reportError(ctx, err)
+			return
+		}
+
+		for i := range tickers {
+			ticker := tickers[i]
+
+			if previous, ok := last[ticker.Market]; !watched[ticker.Market] || (ok && previous == ticker) {
+				continue
+			}
+
+			last[ticker.Market] = ticker
+			feed.publish(ctx, MarketEvent{Kind: TickerEvent, Market: ticker.Market, Ticker: &ticker})
+		}
+	}
+}
+
+// tradesPoller publishes the trades of market that are newer than those seen
+// before, oldest first. The trades returned by the first poll only set the
+// starting point.
+func (feed *MarketDataFeed) tradesPoller(market Market) func(context.Context) {
+	newest, started := 0, false
+
+	return func(ctx context.Context) {
+		trades, err := feed.api.GetMarketTrades(ctx, market)
+		if err != nil {
+			feed.reportError(ctx, err)
+			return
+		}
+
+		sort.Slice(trades, func(i, j int) bool { return trades[i].ID < trades[j].ID })
+
+		for i := range trades {
+			trade := trades[i]
+			if trade.ID <= newest {
+				continue
+			}
+
+			newest = trade.ID
+
+			if started {
+				feed.publish(ctx, MarketEvent{Kind: TradeEvent, Market: market, Trade: &trade})
+			}
+		}
+
+		started = true
+	}
+}
+
+// orderbookPoller publishes the changes of the orderbook of market.
+func (feed *MarketDataFeed) orderbookPoller(market Market) func(context.Context) {
+	watcher := NewOrderbookWatcher(feed.api, market)
+
+	return func(ctx context.Context) {
+		if err := watcher.Poll(ctx); err != nil {
+			feed.reportError(ctx, err)
+			return
+		}
+
+		select {
+		case update := <-watcher.Updates():
+			feed.publish(ctx, MarketEvent{Kind: OrderbookEvent, Market: market, Orderbook: &update})
+		default:
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package qtrade
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// sequenceResponder returns the given bodies in turn, repeating the last one.
+func sequenceResponder(bodies ...string) httpmock.Responder {
+	var mu sync.Mutex
+
+	return func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		body := bodies[0]
+		if len(bodies) > 1 {
+			bodies = bodies[1:]
+		}
+
+		return httpmock.NewStringResponse(200, body), nil
+	}
+}
+
+func TestMarketDataFeed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/tickers", sequenceResponder(
+		`{"data": {"markets": [{"id": 1, "id_hr": "LTC_BTC", "last": "0.01"}, {"id": 2, "id_hr": "BIS_BTC", "last": "1"}]}}`,
+		`{"data": {"markets": [{"id": 1, "id_hr": "LTC_BTC", "last": "0.01"}, {"id": 2, "id_hr": "BIS_BTC", "last": "2"}]}}`,
+		`{"data": {"markets": [{"id": 1, "id_hr": "LTC_BTC", "last": "0.02"}, {"id": 2, "id_hr": "BIS_BTC", "last": "3"}]}}`,
+	))
+	httpmock.RegisterResponder("GET", "http://localhost/v1/market/LTC_BTC/trades", sequenceResponder(
+		`{"data": {"trades": [{"id": 1, "amount": "1", "price": "0.01"}]}}`,
+		`{"data": {"trades": [{"id": 3, "amount": "3", "price": "0.01"}, {"id": 2, "amount": "2", "price": "0.01"}, {"id": 1, "amount": "1", "price": "0.01"}]}}`,
+		`{"data": {"trades": [{"id": 3, "amount": "3", "price": "0.01"}, {"id": 2, "amount": "2", "price": "0.01"}]}}`,
+	))
+	httpmock.RegisterResponder("GET", "http://localhost/v1/orderbook/LTC_BTC", sequenceResponder(
+		`{"data": {"buy": {"0.01": "1"}, "sell": {}, "last_change": 1}}`,
+	))
+
+	feed := NewMarketDataFeed(testClient, LTC_BTC)
+	feed.TickerInterval = time.Millisecond * 10
+	feed.TradesInterval = time.Millisecond * 10
+	feed.OrderbookInterval = time.Millisecond * 10
+
+	all := feed.Subscribe(MarketEventFilter{}, 100)
+	tickers := feed.Subscribe(MarketEventFilter{Markets: []Market{LTC_BTC}, Kinds: TickerEvent}, 100)
+
+	var (
+		mu     sync.Mutex
+		trades []int
+	)
+
+	feed.SubscribeFunc(MarketEventFilter{Kinds: TradeEvent}, func(event MarketEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		trades = append(trades, event.Trade.ID)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+
+	go func() { done <- feed.Run(ctx) }()
+
+	// the last responses repeat without changes, so no event follows these
+	want := map[MarketEventKind]int{TickerEvent: 2, TradeEvent: 2, OrderbookEvent: 1}
+	kinds := map[MarketEventKind]int{}
+	deadline := time.After(time.Second * 5)
+
+	count := func(event MarketEvent) {
+		assert.Equal(t, LTC_BTC, event.Market)
+		kinds[event.Kind]++
+
+		if event.Kind == OrderbookEvent {
+			assert.Equal(t, 1, event.Orderbook.Book.LastChange)
+		}
+	}
+
+wait:
+	for !assert.ObjectsAreEqual(want, kinds) {
+		select {
+		case event := <-all.C:
+			count(event)
+		case <-deadline:
+			assert.Fail(t, "timed out waiting for events", "got %v", kinds)
+			break wait
+		}
+	}
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+
+	for event := range all.C {
+		count(event)
+	}
+
+	assert.Equal(t, want, kinds)
+
+	var prices []string
+	for event := range tickers.C {
+		prices = append(prices, event.Ticker.Last.String())
+	}
+
+	assert.Equal(t, []string{"0.01", "0.02"}, prices)
+
+	// the first poll of trades only sets the starting point
+	mu.Lock()
+	assert.Equal(t, []int{2, 3}, trades)
+	mu.Unlock()
+}
+
+func TestMarketDataFeed_Subscriptions(t *testing.T) {
+	feed := NewMarketDataFeed(testClient, LTC_BTC)
+
+	small := feed.Subscribe(MarketEventFilter{}, 1)
+	other := feed.Subscribe(MarketEventFilter{Markets: []Market{ETH_BTC}}, 10)
+
+	var calls int
+
+	var callback *Subscription
+	callback = feed.SubscribeFunc(MarketEventFilter{Kinds: TradeEvent | OrderbookEvent}, func(event MarketEvent) {
+		calls++
+		callback.Unsubscribe()
+	})
+
+	event := MarketEvent{Kind: TradeEvent, Market: LTC_BTC, Trade: &PublicTrade{ID: 1}}
+	feed.dispatch(event)
+	feed.dispatch(event)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 1, small.Dropped())
+	assert.Len(t, other.C, 0)
+
+	small.Unsubscribe()
+	small.Unsubscribe()
+	feed.dispatch(event)
+
+	got, ok := <-small.C
+	assert.True(t, ok)
+	assert.Equal(t, event, got)
+
+	_, ok = <-small.C
+	assert.False(t, ok)
+}
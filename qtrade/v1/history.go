@@ -0,0 +1,324 @@
+package qtrade
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// WithdrawalsIterator returns withdrawals newest first, paging through
+// GetWithdrawHistory until the oldest withdrawal is reached. Withdrawals are
+// deduplicated by ID.
+//
+// Since and Until may be set before the first call to Next to restrict the
+// withdrawals to those created in [Since, Until). The scan stops at the first
+// withdrawal created before Since.
+type WithdrawalsIterator struct {
+	Since time.Time
+	Until time.Time
+
+	api    PrivateAPI
+	query  HistoryQuery
+	cursor idCursor
+	page   []WithdrawDetails
+}
+
+// NewWithdrawalsIterator returns an iterator over the withdrawals matching
+// query, whose OlderThan and NewerThan fields bound the IDs returned.
+func NewWithdrawalsIterator(api PrivateAPI, query HistoryQuery) *WithdrawalsIterator {
+	return &WithdrawalsIterator{
+		api:    api,
+		query:  query,
+		cursor: newIDCursor(query.OlderThan, query.NewerThan),
+	}
+}
+
+// Next returns the next withdrawal, or ErrIteratorDone once there are none
+// left. A failed request may be retried by calling Next again.
+func (it *WithdrawalsIterator) Next(ctx context.Context) (WithdrawDetails, error) {
+	for len(it.page) == 0 {
+		if it.cursor.done {
+			return WithdrawDetails{}, ErrIteratorDone
+		}
+
+		if err := it.fetch(ctx); err != nil {
+			return WithdrawDetails{}, err
+		}
+	}
+
+	withdraw := it.page[0]
+	it.page = it.page[1:]
+
+	return withdraw, nil
+}
+
+// All returns the remaining withdrawals. On error, it returns the withdrawals
+// read before the failure along with it.
+func (it *WithdrawalsIterator) All(ctx context.Context) ([]WithdrawDetails, error) {
+	var withdraws []WithdrawDetails
+
+	for {
+		withdraw, err := it.Next(ctx)
+		if err == ErrIteratorDone {
+			return withdraws, nil
+		}
+
+		if err != nil {
+			return withdraws, err
+		}
+
+		withdraws = append(withdraws, withdraw)
+	}
+}
+
+func (it *WithdrawalsIterator) fetch(ctx context.Context) error {
+	query := it.query
+	query.OlderThan = it.cursor.oldest
+
+	withdraws, err := it.api.GetWithdrawHistory(ctx, query.Params())
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(withdraws, func(i, j int) bool { return withdraws[i].ID > withdraws[j].ID })
+
+	for _, withdraw := range withdraws {
+		if !it.cursor.take(withdraw.ID) {
+			continue
+		}
+
+		if createdBefore(withdraw.CreatedAt, it.Since) {
+			it.cursor.done = true
+			return nil
+		}
+
+		if !createdAfter(withdraw.CreatedAt, it.Until) {
+			it.page = append(it.page, withdraw)
+		}
+	}
+
+	it.cursor.advance(query.OlderThan)
+
+	return nil
+}
+
+// TransfersIterator returns transfers newest first, paging through
+// GetTransfers until the oldest transfer is reached. Transfers are
+// deduplicated by ID.
+//
+// Since and Until may be set before the first call to Next to restrict the
+// transfers to those created in [Since, Until). The scan stops at the first
+// transfer created before Since.
+type TransfersIterator struct {
+	Since time.Time
+	Until time.Time
+
+	api    PrivateAPI
+	query  HistoryQuery
+	cursor idCursor
+	page   []Transfer
+}
+
+// NewTransfersIterator returns an iterator over the transfers matching query,
+// whose OlderThan and NewerThan fields bound the IDs returned.
+func NewTransfersIterator(api PrivateAPI, query HistoryQuery) *TransfersIterator {
+	return &TransfersIterator{
+		api:    api,
+		query:  query,
+		cursor: newIDCursor(query.OlderThan, query.NewerThan),
+	}
+}
+
+// Next returns the next transfer, or ErrIteratorDone once there are none
+// left. A failed request may be retried by calling Next again.
+func (it *TransfersIterator) Next(ctx context.Context) (Transfer, error) {
+	for len(it.page) == 0 {
+		if it.cursor.done {
+			return Transfer{}, ErrIteratorDone
+		}
+
+		if err := it.fetch(ctx); err != nil {
+			return Transfer{}, err
+		}
+	}
+
+	transfer := it.page[0]
+	it.page = it.page[1:]
+
+	return transfer, nil
+}
+
+// All returns the remaining transfers. On error, it returns the transfers
+// read before the failure along with it.
+func (it *TransfersIterator) All(ctx context.Context) ([]Transfer, error) {
+	var transfers []Transfer
+
+	for {
+		transfer, err := it.Next(ctx)
+		if err == ErrIteratorDone {
+			return transfers, nil
+		}
+
+		if err != nil {
+			return transfers, err
+		}
+
+		transfers = append(transfers, transfer)
+	}
+}
+
+func (it *TransfersIterator) fetch(ctx context.Context) error {
+	query := it.query
+	query.OlderThan = it.cursor.oldest
+
+	transfers, err := it.api.GetTransfers(ctx, query.Params())
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(transfers, func(i, j int) bool { return transfers[i].ID > transfers[j].ID })
+
+	for _, transfer := range transfers {
+		if !it.cursor.take(transfer.ID) {
+			continue
+		}
+
+		if createdBefore(transfer.CreatedAt, it.Since) {
+			it.cursor.done = true
+			return nil
+		}
+
+		if !createdAfter(transfer.CreatedAt, it.Until) {
+			it.page = append(it.page, transfer)
+		}
+	}
+
+	it.cursor.advance(query.OlderThan)
+
+	return nil
+}
+
+// DefaultDepositPageLimit is the PageLimit of a new DepositsIterator.
+const DefaultDepositPageLimit = 1000
+
+// DepositsIterator returns deposits newest first, deduplicated by ID.
+//
+// Unlike the other histories, deposits cannot be paged: their IDs are
+// transaction references rather than sequence numbers, so older_than has
+// nothing to compare them with, and the API takes no time cursor. The history
+// is therefore read with a single GetDepositHistory request for at most
+// PageLimit deposits. If all of them are returned, older ones may be missing,
+// and Next fails with ErrHistoryTruncated after the last deposit instead of
+// returning ErrIteratorDone, so that an export is never silently incomplete.
+//
+// Since and Until may be set before the first call to Next to restrict the
+// deposits to those created in [Since, Until). The scan stops at the first
+// deposit created before Since; a history that reaches back to Since is not
+// truncated.
+type DepositsIterator struct {
+	Since time.Time
+	Until time.Time
+	// PageLimit is sent as the limit parameter of the request. It defaults to
+	// DefaultDepositPageLimit, and may be raised before the first call to
+	// Next for accounts with more deposits.
+	PageLimit int
+
+	api       PrivateAPI
+	query     HistoryQuery
+	seen      map[string]bool
+	done      bool
+	truncated bool
+	page      []DepositDetails
+}
+
+// NewDepositsIterator returns an iterator over the deposits matching query.
+func NewDepositsIterator(api PrivateAPI, query HistoryQuery) *DepositsIterator {
+	return &DepositsIterator{
+		PageLimit: DefaultDepositPageLimit,
+		api:       api,
+		query:     query,
+		seen:      map[string]bool{},
+	}
+}
+
+// Next returns the next deposit, or ErrIteratorDone once there are none left,
+// or ErrHistoryTruncated if the history may be incomplete. A failed request
+// may be retried by calling Next again.
+func (it *DepositsIterator) Next(ctx context.Context) (DepositDetails, error) {
+	for len(it.page) == 0 {
+		if it.done && it.truncated {
+			return DepositDetails{}, ErrHistoryTruncated
+		}
+
+		if it.done {
+			return DepositDetails{}, ErrIteratorDone
+		}
+
+		if err := it.fetch(ctx); err != nil {
+			return DepositDetails{}, err
+		}
+	}
+
+	deposit := it.page[0]
+	it.page = it.page[1:]
+
+	return deposit, nil
+}
+
+// All returns the remaining deposits. On error, including
+// ErrHistoryTruncated, it returns the deposits read along with it.
+func (it *DepositsIterator) All(ctx context.Context) ([]DepositDetails, error) {
+	var deposits []DepositDetails
+
+	for {
+		deposit, err := it.Next(ctx)
+		if err == ErrIteratorDone {
+			return deposits, nil
+		}
+
+		if err != nil {
+			return deposits, err
+		}
+
+		deposits = append(deposits, deposit)
+	}
+}
+
+func (it *DepositsIterator) fetch(ctx context.Context) error {
+	limit := it.PageLimit
+	if limit <= 0 {
+		limit = DefaultDepositPageLimit
+	}
+
+	params := it.query.Params()
+	setInt(params, "limit", limit)
+
+	deposits, err := it.api.GetDepositHistory(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(deposits, func(i, j int) bool { return deposits[i].CreatedAt.After(deposits[j].CreatedAt) })
+
+	it.done = true
+	it.truncated = len(deposits) >= limit
+
+	for _, deposit := range deposits {
+		if it.seen[deposit.ID] {
+			continue
+		}
+
+		it.seen[deposit.ID] = true
+
+		if createdBefore(deposit.CreatedAt, it.Since) {
+			it.truncated = false
+			return nil
+		}
+
+		if !createdAfter(deposit.CreatedAt, it.Until) {
+			it.page = append(it.page, deposit)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package qtrade
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithdrawalsIterator(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	withdraws := make([]WithdrawDetails, 5)
+	for i := range withdraws {
+		withdraws[i] = WithdrawDetails{
+			Amount:    DecimalFromInt(int64(i + 1)),
+			CreatedAt: iteratorStart.Add(time.Duration(i) * time.Hour),
+			Currency:  LTC,
+			ID:        i + 1,
+		}
+	}
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/user/withdraws",
+		func(req *http.Request) (*http.Response, error) {
+			result := new(GetWithdrawHistoryResult)
+			result.Data.Withdraws = []WithdrawDetails{}
+
+			for _, id := range pageIDs(t, req, len(withdraws), 2) {
+				result.Data.Withdraws = append(result.Data.Withdraws, withdraws[id-1])
+			}
+
+			return httpmock.NewJsonResponse(200, result)
+		})
+
+	it := NewWithdrawalsIterator(testClient, HistoryQuery{})
+	it.Until = iteratorStart.Add(time.Hour * 4)
+
+	got, err := it.All(context.Background())
+	if assert.NoError(t, err) && assert.Len(t, got, 4) {
+		assert.Equal(t, 4, got[0].ID)
+		assert.Equal(t, MustParseDecimal("1"), got[3].Amount)
+	}
+
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+}
+
+func TestTransfersIterator(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	transfer := func(id int) Transfer {
+		return Transfer{
+			Amount:    MustParseDecimal("0.5"),
+			CreatedAt: iteratorStart.Add(time.Duration(id) * time.Hour),
+			Currency:  BTC,
+			ID:        id,
+		}
+	}
+
+	// pages overlap, and repeat a transfer within a page
+	pages := map[string][]Transfer{
+		"":  {transfer(6), transfer(5), transfer(5), transfer(4)},
+		"4": {transfer(4), transfer(3), transfer(2)},
+		"2": {},
+	}
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/user/transfers",
+		func(req *http.Request) (*http.Response, error) {
+			result := new(GetTransfersResult)
+			result.Data.Transfers = pages[req.URL.Query().Get("older_than")]
+
+			return httpmock.NewJsonResponse(200, result)
+		})
+
+	it := NewTransfersIterator(testClient, HistoryQuery{})
+	it.Since = iteratorStart.Add(time.Hour * 3)
+
+	got, err := it.All(context.Background())
+	if assert.NoError(t, err) && assert.Len(t, got, 4) {
+		for i, id := range []int{6, 5, 4, 3} {
+			assert.Equal(t, id, got[i].ID)
+		}
+	}
+
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestDepositsIterator(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/user/deposits?limit=1000",
+		httpmock.NewStringResponder(200, `{"data": {"deposits": [
+			{"amount": "0.25", "created_at": "2019-01-08T21:15:18Z", "currency": "BTC", "id": "1:855e"},
+			{"amount": "1", "created_at": "2019-03-02T04:05:51Z", "currency": "BTC", "id": "0:ab5e"},
+			{"amount": "1", "created_at": "2019-03-02T04:05:51Z", "currency": "BTC", "id": "0:ab5e"},
+			{"amount": "2", "created_at": "2018-12-01T00:00:00Z", "currency": "LTC", "id": "0:ffff"}
+		]}}`))
+
+	it := NewDepositsIterator(testClient, HistoryQuery{})
+	it.Since = time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	got, err := it.All(context.Background())
+	if assert.NoError(t, err) && assert.Len(t, got, 2) {
+		assert.Equal(t, "0:ab5e", got[0].ID)
+		assert.Equal(t, "1:855e", got[1].ID)
+	}
+
+	_, err = it.Next(context.Background())
+	assert.Equal(t, ErrIteratorDone, err)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestDepositsIterator_Truncated(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var limit string
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/user/deposits",
+		func(req *http.Request) (*http.Response, error) {
+			limit = req.URL.Query().Get("limit")
+
+			return httpmock.NewStringResponse(200, `{"data": {"deposits": [
+				{"amount": "0.25", "created_at": "2019-01-08T21:15:18Z", "currency": "BTC", "id": "1:855e"},
+				{"amount": "1", "created_at": "2019-03-02T04:05:51Z", "currency": "BTC", "id": "0:ab5e"}
+			]}}`), nil
+		})
+
+	testCases := []struct {
+		name      string
+		pageLimit int
+		since     time.Time
+		wantErr   error
+		wantLen   int
+		wantLimit string
+	}{
+		{name: "default limit", wantErr: nil, wantLen: 2, wantLimit: "1000"},
+		{name: "negative limit", pageLimit: -1, wantErr: nil, wantLen: 2, wantLimit: "1000"},
+		{name: "below limit", pageLimit: 3, wantErr: nil, wantLen: 2, wantLimit: "3"},
+		{name: "full page", pageLimit: 2, wantErr: ErrHistoryTruncated, wantLen: 2, wantLimit: "2"},
+		{name: "full page reaching since", pageLimit: 2, since: time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC), wantErr: nil, wantLen: 1, wantLimit: "2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			it := NewDepositsIterator(testClient, HistoryQuery{})
+			if tc.pageLimit != 0 {
+				it.PageLimit = tc.pageLimit
+			}
+
+			it.Since = tc.since
+
+			got, err := it.All(context.Background())
+			assert.Equal(t, tc.wantErr, err)
+			assert.Len(t, got, tc.wantLen)
+			assert.Equal(t, tc.wantLimit, limit)
+		})
+	}
+}
@@ -0,0 +1,293 @@
+// Command genenums generates the Market and Currency constants of the qtrade
+// package, and the tables derived from them, from a snapshot of the
+// /v1/common endpoint.
+//
+// Usage:
+//
+//	genenums [-fetch url] -in internal/genenums/common.json -out enums_gen.go
+//
+// The snapshot may be the raw API response or just its "data" object. With
+// -fetch, the response of url is first saved as the snapshot, so that
+//
+//	go run ./internal/genenums -fetch https://api.qtrade.io/v1/common
+//
+// captures a fresh snapshot and regenerates the code from it.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+type currency struct {
+	Code      string `json:"code"`
+	Precision int    `json:"precision"`
+}
+
+type market struct {
+	ID             int    `json:"id"`
+	MarketCurrency string `json:"market_currency"`
+	BaseCurrency   string `json:"base_currency"`
+}
+
+type commonData struct {
+	Currencies []currency `json:"currencies"`
+	Markets    []market   `json:"markets"`
+}
+
+// constCurrency and constMarket are the generated constants.
+type constCurrency struct {
+	Name      string
+	Code      string
+	Precision int
+}
+
+type constMarket struct {
+	Name           string
+	ID             int
+	String         string
+	MarketCurrency string
+	BaseCurrency   string
+}
+
+func main() {
+	in := flag.String("in", "internal/genenums/common.json", "`file` holding a /v1/common response")
+	out := flag.String("out", "enums_gen.go", "`file` to write the generated code to")
+	fetch := flag.String("fetch", "", "`url` of a /v1/common endpoint to save to -in first")
+	flag.Parse()
+
+	if *fetch != "" {
+		if err := capture(*fetch, *in); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	snapshot, err := ioutil.ReadFile(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := generate(snapshot, *in)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// capture saves the /v1/common response of url to path. The response is
+// checked to be a snapshot that generate can use before path is replaced.
+func capture(url, path string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	snapshot, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", url, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	}
+
+	if _, err := generate(snapshot, url); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, snapshot, 0o644)
+}
+
+// parse decodes a /v1/common snapshot, with or without the "data" envelope.
+func parse(snapshot []byte) (*commonData, error) {
+	var envelope struct {
+		Data *commonData `json:"data"`
+	}
+
+	if err := json.Unmarshal(snapshot, &envelope); err != nil {
+		return nil, err
+	}
+
+	if envelope.Data != nil {
+		return envelope.Data, nil
+	}
+
+	data := new(commonData)
+	if err := json.Unmarshal(snapshot, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// currencyName returns the Go constant name of a currency code, such as PUSD
+// for pUSD.
+func currencyName(code string) string {
+	return strings.ToUpper(code)
+}
+
+// marketName returns the Go constant name of a market, such as BTC_pUSD. The
+// base currency keeps its case so that names match the exchange's.
+func marketName(m market) string {
+	return currencyName(m.MarketCurrency) + "_" + m.BaseCurrency
+}
+
+// generate returns the formatted source of the generated file for snapshot,
+// which was read from source.
+func generate(snapshot []byte, source string) ([]byte, error) {
+	data, err := parse(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", source, err)
+	}
+
+	currencies := make([]constCurrency, 0, len(data.Currencies))
+	codes := map[string]bool{}
+	names := map[string]string{}
+
+	for _, c := range data.Currencies {
+		name := currencyName(c.Code)
+		if other, ok := names[name]; ok {
+			return nil, fmt.Errorf("currencies %s and %s would both be named %s", other, c.Code, name)
+		}
+
+		names[name] = c.Code
+		codes[c.Code] = true
+		currencies = append(currencies, constCurrency{Name: name, Code: c.Code, Precision: c.Precision})
+	}
+
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i].Name < currencies[j].Name })
+
+	markets := make([]constMarket, 0, len(data.Markets))
+	ids := map[int]bool{}
+
+	for _, m := range data.Markets {
+		if ids[m.ID] {
+			return nil, fmt.Errorf("market %v is listed twice", m.ID)
+		}
+
+		for _, code := range []string{m.MarketCurrency, m.BaseCurrency} {
+			if !codes[code] {
+				return nil, fmt.Errorf("market %v uses unlisted currency %q", m.ID, code)
+			}
+		}
+
+		name := marketName(m)
+		if other, ok := names[name]; ok {
+			return nil, fmt.Errorf("market %v and %s would both be named %s", m.ID, other, name)
+		}
+
+		ids[m.ID] = true
+		names[name] = fmt.Sprint("market ", m.ID)
+		markets = append(markets, constMarket{
+			Name:           name,
+			ID:             m.ID,
+			String:         m.MarketCurrency + "_" + m.BaseCurrency,
+			MarketCurrency: currencyName(m.MarketCurrency),
+			BaseCurrency:   currencyName(m.BaseCurrency),
+		})
+	}
+
+	sort.Slice(markets, func(i, j int) bool { return markets[i].ID < markets[j].ID })
+
+	var buf bytes.Buffer
+
+	err = fileTemplate.Execute(&buf, map[string]interface{}{
+		"Source":     source,
+		"Currencies": currencies,
+		"Markets":    markets,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+var fileTemplate = template.Must(template.New("").Funcs(template.FuncMap{"upper": strings.ToUpper}).Parse(`// Code generated by genenums from {{.Source}}. DO NOT EDIT.
+
+//go:build !test
+// +build !test
+
+package qtrade
+
+const (
+{{- range .Currencies}}
+	{{.Name}} Currency = {{printf "%q" .Code}}
+{{- end}}
+)
+
+// parseCurrency returns the currency with the given code in upper case.
+func parseCurrency(code string) (Currency, bool) {
+	switch code {
+{{- range .Currencies}}
+	case {{printf "%q" (upper .Code)}}:
+		return {{.Name}}, true
+{{- end}}
+	}
+
+	return "", false
+}
+
+// CurrencyDecimalPlaces is the number of decimal places of each currency.
+var CurrencyDecimalPlaces = map[Currency]int{
+{{- range .Currencies}}
+	{{.Name}}: {{.Precision}},
+{{- end}}
+}
+
+// nolint: golint
+const (
+{{- range .Markets}}
+	{{.Name}} Market = {{.ID}}
+{{- end}}
+)
+
+func (m Market) String() string {
+	switch m {
+{{- range .Markets}}
+	case {{.Name}}:
+		return {{printf "%q" .String}}
+{{- end}}
+	}
+
+	return "unknown"
+}
+
+// parseMarket returns the market with the given name in upper case, such as
+// "BTC_PUSD".
+func parseMarket(name string) (Market, bool) {
+	switch name {
+{{- range .Markets}}
+	case {{printf "%q" (upper .String)}}:
+		return {{.Name}}, true
+{{- end}}
+	}
+
+	return 0, false
+}
+
+// currencies returns the market and base currencies of m.
+func (m Market) currencies() (Currency, Currency, bool) {
+	switch m {
+{{- range .Markets}}
+	case {{.Name}}:
+		return {{.MarketCurrency}}, {{.BaseCurrency}}, true
+{{- end}}
+	}
+
+	return "", "", false
+}
+`))
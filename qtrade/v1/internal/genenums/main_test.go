@@ -0,0 +1,127 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate_UpToDate(t *testing.T) {
+	snapshot, err := ioutil.ReadFile("common.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	committed, err := ioutil.ReadFile("../../enums_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := generate(snapshot, "internal/genenums/common.json")
+	if assert.NoError(t, err) {
+		assert.Equal(t, string(committed), string(got), "enums_gen.go is stale, run go generate")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		snapshot string
+		want     []string
+		wantErr  string
+	}{
+		{
+			name: "without data envelope",
+			snapshot: `{"currencies": [{"code": "BTC", "precision": 8}, {"code": "pUSD", "precision": 8}],
+				"markets": [{"id": 43, "market_currency": "BTC", "base_currency": "pUSD"}]}`,
+			want: []string{
+				`PUSD Currency = "pUSD"`,
+				`BTC_pUSD Market = 43`,
+				`return "BTC_pUSD"`,
+				`case "BTC_PUSD":`,
+				`return BTC, PUSD, true`,
+			},
+		},
+		{
+			name:     "invalid json",
+			snapshot: `{"data": [`,
+			wantErr:  "failed to parse",
+		},
+		{
+			name: "duplicate market",
+			snapshot: `{"data": {"currencies": [{"code": "LTC"}, {"code": "BTC"}],
+				"markets": [{"id": 1, "market_currency": "LTC", "base_currency": "BTC"},
+				{"id": 1, "market_currency": "LTC", "base_currency": "BTC"}]}}`,
+			wantErr: "market 1 is listed twice",
+		},
+		{
+			name: "unlisted currency",
+			snapshot: `{"data": {"currencies": [{"code": "BTC"}],
+				"markets": [{"id": 1, "market_currency": "LTC", "base_currency": "BTC"}]}}`,
+			wantErr: `market 1 uses unlisted currency "LTC"`,
+		},
+		{
+			name:     "clashing currency names",
+			snapshot: `{"data": {"currencies": [{"code": "pusd"}, {"code": "PUSD"}]}}`,
+			wantErr:  "would both be named PUSD",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := generate([]byte(tc.snapshot), "test.json")
+			if tc.wantErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.wantErr)
+				}
+
+				return
+			}
+
+			if assert.NoError(t, err) {
+				for _, want := range tc.want {
+					assert.Contains(t, string(got), want)
+				}
+			}
+		})
+	}
+}
+
+func TestCapture(t *testing.T) {
+	snapshot := `{"data": {"currencies": [{"code": "LTC", "precision": 8}, {"code": "BTC", "precision": 8}],
+		"markets": [{"id": 1, "market_currency": "LTC", "base_currency": "BTC"}]}}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/common":
+			w.Write([]byte(snapshot))
+		case "/v1/invalid":
+			w.Write([]byte(`{"data": [`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "common.json")
+
+	if assert.NoError(t, capture(server.URL+"/v1/common", path)) {
+		saved, err := ioutil.ReadFile(path)
+		if assert.NoError(t, err) {
+			assert.Equal(t, snapshot, string(saved))
+		}
+	}
+
+	// failed captures keep the previous snapshot
+	assert.Error(t, capture(server.URL+"/v1/missing", path))
+	assert.Error(t, capture(server.URL+"/v1/invalid", path))
+
+	saved, err := ioutil.ReadFile(path)
+	if assert.NoError(t, err) {
+		assert.Equal(t, snapshot, string(saved))
+	}
+}
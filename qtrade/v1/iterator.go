@@ -0,0 +1,259 @@
+package qtrade
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrIteratorDone is returned by the Next method of an iterator once every
+// record has been returned.
+var ErrIteratorDone = errors.New("no more records")
+
+// ErrHistoryTruncated is returned by DepositsIterator in place of
+// ErrIteratorDone when the history it read was cut short by its page limit.
+var ErrHistoryTruncated = errors.New("history may be truncated")
+
+// idCursor pages backward through records with increasing integer IDs, using
+// the older_than parameter.
+type idCursor struct {
+	oldest    int
+	newerThan int
+	done      bool
+}
+
+func newIDCursor(olderThan, newerThan int) idCursor {
+	return idCursor{oldest: olderThan, newerThan: newerThan}
+}
+
+// take reports whether a record with the given ID has not been returned yet,
+// and records it. Records must be taken newest first. Duplicates, and records
+// repeated by a server that ignores the cursor, are skipped.
+func (c *idCursor) take(id int) bool {
+	if (c.oldest != 0 && id >= c.oldest) || id <= c.newerThan {
+		return false
+	}
+
+	c.oldest = id
+
+	return true
+}
+
+// advance ends the iteration if the page requested with older_than set to
+// start held no new records, or reached newer_than.
+func (c *idCursor) advance(start int) {
+	c.done = c.oldest == start || c.oldest <= c.newerThan+1
+}
+
+// createdBefore reports whether t is before since, when it is set. This ends
+// a backward scan.
+func createdBefore(t, since time.Time) bool {
+	return !since.IsZero() && t.Before(since)
+}
+
+// createdAfter reports whether t is at or after until, when it is set.
+func createdAfter(t, until time.Time) bool {
+	return !until.IsZero() && !t.Before(until)
+}
+
+// OrdersIterator returns orders newest first, paging through GetOrders until
+// the oldest order is reached. Requests made through a *Client wait for its
+// rate limiter.
+//
+// Since and Until may be set before the first call to Next to restrict the
+// orders to those created in [Since, Until). The scan stops at the first
+// order created before Since.
+type OrdersIterator struct {
+	Since time.Time
+	Until time.Time
+
+	api    PrivateAPI
+	query  OrdersQuery
+	cursor idCursor
+	page   []Order
+}
+
+// NewOrdersIterator returns an iterator over the orders matching query. Its
+// OlderThan and NewerThan fields bound the IDs returned, and Market, when set,
+// is also checked on every order.
+func NewOrdersIterator(api PrivateAPI, query OrdersQuery) *OrdersIterator {
+	return &OrdersIterator{
+		api:    api,
+		query:  query,
+		cursor: newIDCursor(query.OlderThan, query.NewerThan),
+	}
+}
+
+// Next returns the next order, or ErrIteratorDone once there are none left.
+// A failed request may be retried by calling Next again.
+func (it *OrdersIterator) Next(ctx context.Context) (Order, error) {
+	for len(it.page) == 0 {
+		if it.cursor.done {
+			return Order{}, ErrIteratorDone
+		}
+
+		if err := it.fetch(ctx); err != nil {
+			return Order{}, err
+		}
+	}
+
+	order := it.page[0]
+	it.page = it.page[1:]
+
+	return order, nil
+}
+
+// All returns the remaining orders. On error, it returns the orders read
+// before the failure along with it.
+func (it *OrdersIterator) All(ctx context.Context) ([]Order, error) {
+	var orders []Order
+
+	for {
+		order, err := it.Next(ctx)
+		if err == ErrIteratorDone {
+			return orders, nil
+		}
+
+		if err != nil {
+			return orders, err
+		}
+
+		orders = append(orders, order)
+	}
+}
+
+func (it *OrdersIterator) fetch(ctx context.Context) error {
+	query := it.query
+	query.OlderThan = it.cursor.oldest
+
+	orders, err := it.api.GetOrders(ctx, query.Params())
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(orders, func(i, j int) bool { return orders[i].ID > orders[j].ID })
+
+	for _, order := range orders {
+		if !it.cursor.take(order.ID) {
+			continue
+		}
+
+		if createdBefore(order.CreatedAt, it.Since) {
+			it.cursor.done = true
+			return nil
+		}
+
+		if createdAfter(order.CreatedAt, it.Until) || (it.query.Market != 0 && order.Market != it.query.Market) {
+			continue
+		}
+
+		it.page = append(it.page, order)
+	}
+
+	it.cursor.advance(query.OlderThan)
+
+	return nil
+}
+
+// TradesIterator returns trades newest first, paging through GetTrades until
+// the oldest trade is reached. Requests made through a *Client wait for its
+// rate limiter.
+//
+// Since and Until may be set before the first call to Next to restrict the
+// trades to those created in [Since, Until). The scan stops at the first
+// trade created before Since.
+type TradesIterator struct {
+	Since time.Time
+	Until time.Time
+
+	api    PrivateAPI
+	query  TradesQuery
+	cursor idCursor
+	page   []PrivateTrade
+}
+
+// NewTradesIterator returns an iterator over the trades matching query. Its
+// OlderThan and NewerThan fields bound the IDs returned, and Market, when set,
+// is also checked on every trade. Desc is always set on the requests.
+func NewTradesIterator(api PrivateAPI, query TradesQuery) *TradesIterator {
+	query.Desc = true
+
+	return &TradesIterator{
+		api:    api,
+		query:  query,
+		cursor: newIDCursor(query.OlderThan, query.NewerThan),
+	}
+}
+
+// Next returns the next trade, or ErrIteratorDone once there are none left.
+// A failed request may be retried by calling Next again.
+func (it *TradesIterator) Next(ctx context.Context) (PrivateTrade, error) {
+	for len(it.page) == 0 {
+		if it.cursor.done {
+			return PrivateTrade{}, ErrIteratorDone
+		}
+
+		if err := it.fetch(ctx); err != nil {
+			return PrivateTrade{}, err
+		}
+	}
+
+	trade := it.page[0]
+	it.page = it.page[1:]
+
+	return trade, nil
+}
+
+// All returns the remaining trades. On error, it returns the trades read
+// before the failure along with it.
+func (it *TradesIterator) All(ctx context.Context) ([]PrivateTrade, error) {
+	var trades []PrivateTrade
+
+	for {
+		trade, err := it.Next(ctx)
+		if err == ErrIteratorDone {
+			return trades, nil
+		}
+
+		if err != nil {
+			return trades, err
+		}
+
+		trades = append(trades, trade)
+	}
+}
+
+func (it *TradesIterator) fetch(ctx context.Context) error {
+	query := it.query
+	query.OlderThan = it.cursor.oldest
+
+	trades, err := it.api.GetTrades(ctx, query.Params())
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(trades, func(i, j int) bool { return trades[i].ID > trades[j].ID })
+
+	for _, trade := range trades {
+		if !it.cursor.take(trade.ID) {
+			continue
+		}
+
+		if createdBefore(trade.CreatedAt, it.Since) {
+			it.cursor.done = true
+			return nil
+		}
+
+		if createdAfter(trade.CreatedAt, it.Until) || (it.query.Market != 0 && trade.Market != it.query.Market) {
+			continue
+		}
+
+		it.page = append(it.page, trade)
+	}
+
+	it.cursor.advance(query.OlderThan)
+
+	return nil
+}
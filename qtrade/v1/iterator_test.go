@@ -0,0 +1,243 @@
+package qtrade
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var iteratorStart = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// testOrders returns n orders with IDs 1 to n, created an hour apart and
+// alternating between LTC_BTC and ETH_BTC.
+func testOrders(n int) []Order {
+	orders := make([]Order, n)
+	for i := range orders {
+		orders[i] = Order{
+			ID:        i + 1,
+			CreatedAt: iteratorStart.Add(time.Duration(i) * time.Hour),
+			Market:    []Market{LTC_BTC, ETH_BTC}[i%2],
+			OrderType: BuyLimit,
+			Price:     MustParseDecimal("0.01"),
+		}
+	}
+
+	return orders
+}
+
+// pageIDs returns up to pageSize IDs between 1 and n, newest first, that match
+// the older_than parameter of req.
+func pageIDs(t *testing.T, req *http.Request, n, pageSize int) []int {
+	olderThan := n + 1
+
+	if v := req.URL.Query().Get("older_than"); v != "" {
+		var err error
+
+		olderThan, err = strconv.Atoi(v)
+		assert.NoError(t, err)
+	}
+
+	var ids []int
+
+	for id := olderThan - 1; id >= 1 && len(ids) < pageSize; id-- {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func registerOrderPages(t *testing.T, orders []Order, pageSize int) {
+	httpmock.RegisterResponder("GET", "http://localhost/v1/user/orders",
+		func(req *http.Request) (*http.Response, error) {
+			result := new(GetOrdersResult)
+			result.Data.Orders = []Order{}
+
+			for _, id := range pageIDs(t, req, len(orders), pageSize) {
+				result.Data.Orders = append(result.Data.Orders, orders[id-1])
+			}
+
+			return httpmock.NewJsonResponse(200, result)
+		})
+}
+
+func orderIDs(orders []Order) []int {
+	ids := make([]int, len(orders))
+	for i, order := range orders {
+		ids[i] = order.ID
+	}
+
+	return ids
+}
+
+func TestOrdersIterator(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     OrdersQuery
+		since     time.Time
+		until     time.Time
+		want      []int
+		wantCalls int
+	}{
+		{
+			name:      "all orders",
+			want:      []int{7, 6, 5, 4, 3, 2, 1},
+			wantCalls: 4,
+		},
+		{
+			name:      "older than",
+			query:     OrdersQuery{OlderThan: 5},
+			want:      []int{4, 3, 2, 1},
+			wantCalls: 2,
+		},
+		{
+			name:      "newer than",
+			query:     OrdersQuery{NewerThan: 4},
+			want:      []int{7, 6, 5},
+			wantCalls: 2,
+		},
+		{
+			name:      "market",
+			query:     OrdersQuery{Market: ETH_BTC},
+			want:      []int{6, 4, 2},
+			wantCalls: 4,
+		},
+		{
+			name:      "time window",
+			since:     iteratorStart.Add(time.Hour * 2),
+			until:     iteratorStart.Add(time.Hour * 5),
+			want:      []int{5, 4, 3},
+			wantCalls: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			registerOrderPages(t, testOrders(7), 2)
+
+			it := NewOrdersIterator(testClient, tc.query)
+			it.Since = tc.since
+			it.Until = tc.until
+
+			got, err := it.All(context.Background())
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.want, orderIDs(got))
+			}
+
+			assert.Equal(t, tc.wantCalls, httpmock.GetTotalCallCount())
+
+			_, err = it.Next(context.Background())
+			assert.Equal(t, ErrIteratorDone, err)
+		})
+	}
+}
+
+func TestOrdersIterator_IgnoredCursor(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// a server that ignores older_than must not cause an endless loop
+	httpmock.RegisterResponder("GET", "http://localhost/v1/user/orders",
+		func(req *http.Request) (*http.Response, error) {
+			result := new(GetOrdersResult)
+			result.Data.Orders = testOrders(4)[1:]
+
+			return httpmock.NewJsonResponse(200, result)
+		})
+
+	got, err := NewOrdersIterator(testClient, OrdersQuery{}).All(context.Background())
+	if assert.NoError(t, err) {
+		assert.Equal(t, []int{4, 3, 2}, orderIDs(got))
+	}
+
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestOrdersIterator_Error(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	registerOrderPages(t, testOrders(3), 2)
+
+	it := NewOrdersIterator(testClient, OrdersQuery{})
+
+	first, err := it.Next(context.Background())
+	if assert.NoError(t, err) {
+		assert.Equal(t, 3, first.ID)
+	}
+
+	_, err = it.Next(context.Background())
+	assert.NoError(t, err)
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/user/orders",
+		httpmock.NewStringResponder(401, `{"errors": [{"code": "invalid_auth", "title": "Invalid HMAC signature"}]}`))
+
+	_, err = it.Next(context.Background())
+	assert.Error(t, err)
+
+	// the failed page is fetched again
+	registerOrderPages(t, testOrders(3), 2)
+
+	got, err := it.All(context.Background())
+	if assert.NoError(t, err) {
+		assert.Equal(t, []int{1}, orderIDs(got))
+	}
+}
+
+func TestTradesIterator(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	trades := make([]PrivateTrade, 5)
+	for i := range trades {
+		trades[i] = PrivateTrade{
+			ID:        i + 1,
+			CreatedAt: iteratorStart.Add(time.Duration(i) * time.Hour),
+			Market:    LTC_BTC,
+			Side:      "buy",
+		}
+	}
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/user/trades",
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "true", req.URL.Query().Get("desc"))
+			assert.Equal(t, "LTC_BTC", req.URL.Query().Get("market_string"))
+
+			result := new(GetTradesResult)
+			result.Data.Trades = []PrivateTrade{}
+
+			for _, id := range pageIDs(t, req, len(trades), 2) {
+				result.Data.Trades = append(result.Data.Trades, trades[id-1])
+			}
+
+			return httpmock.NewJsonResponse(200, result)
+		})
+
+	it := NewTradesIterator(testClient, TradesQuery{Market: LTC_BTC})
+	it.Since = iteratorStart.Add(time.Hour)
+
+	var ids []int
+
+	for {
+		trade, err := it.Next(context.Background())
+		if err == ErrIteratorDone {
+			break
+		}
+
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		ids = append(ids, trade.ID)
+	}
+
+	assert.Equal(t, []int{5, 4, 3, 2}, ids)
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+}
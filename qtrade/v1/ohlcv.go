@@ -0,0 +1,122 @@
+package qtrade
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// OHLCVLimit is the most slices that one GetOHLCV request returns, whatever
+// its limit parameter. FetchOHLCVRange requests ranges of this many intervals
+// at once, and qtradetest.Server applies the same cap, so that tests catch
+// ranges that would be cut short.
+const OHLCVLimit = 1000
+
+// FetchOHLCVRange returns the slices of market starting in [start, end),
+// oldest first. Long ranges are split into several GetOHLCV requests, which
+// wait for the rate limiter, and slices returned by more than one request are
+// only included once. Each request covers at most OHLCVLimit intervals.
+// Intervals without trades may be missing; use FillOHLCVGaps for a
+// contiguous series.
+func (client *Client) FetchOHLCVRange(ctx context.Context, market Market, interval Interval, start, end time.Time) ([]OHLCVSlice, error) {
+	step := interval.Duration()
+	if step == 0 {
+		return nil, errors.Errorf("unknown interval %q", interval)
+	}
+
+	if !start.Before(end) {
+		return nil, errors.Errorf("invalid OHLCV range %v to %v", start, end)
+	}
+
+	byTime := map[int64]OHLCVSlice{}
+
+	for chunkStart := start.Truncate(step); chunkStart.Before(end); chunkStart = chunkStart.Add(step * OHLCVLimit) {
+		chunkEnd := chunkStart.Add(step * OHLCVLimit)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+
+		slices, err := client.QueryOHLCV(ctx, market, interval, OHLCVQuery{
+			Start: chunkStart,
+			End:   chunkEnd,
+			Limit: OHLCVLimit,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, slice := range slices {
+			if !slice.Time.Before(start) && slice.Time.Before(end) {
+				byTime[slice.Time.UnixNano()] = slice
+			}
+		}
+	}
+
+	result := make([]OHLCVSlice, 0, len(byTime))
+	for _, slice := range byTime {
+		result = append(result, slice)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Time.Before(result[j].Time) })
+
+	return result, nil
+}
+
+// FillOHLCVGaps returns slices, which must be sorted oldest first, with a flat
+// slice added for every missing interval starting in [start, end), as for
+// FetchOHLCVRange. A flat slice opens, closes and has its high and low at the
+// previous close, with no volume. Intervals before the first slice are filled
+// at its open instead. Nothing is added if slices is empty, since no price is
+// known.
+func FillOHLCVGaps(slices []OHLCVSlice, interval Interval, start, end time.Time) []OHLCVSlice {
+	step := interval.Duration()
+	if step == 0 || len(slices) == 0 {
+		return slices
+	}
+
+	first := start.Truncate(step)
+	if first.Before(start) {
+		first = first.Add(step)
+	}
+
+	result := make([]OHLCVSlice, 0, len(slices))
+
+	for t := first; t.Before(slices[0].Time) && t.Before(end); t = t.Add(step) {
+		result = append(result, flatOHLCVSlice(slices[0].Open, t))
+	}
+
+	result = append(result, slices[0])
+
+	for _, slice := range slices[1:] {
+		prev := result[len(result)-1]
+
+		for t := prev.Time.Add(step); t.Before(slice.Time); t = t.Add(step) {
+			result = append(result, flatOHLCVSlice(prev.Close, t))
+		}
+
+		result = append(result, slice)
+	}
+
+	last := result[len(result)-1]
+
+	for t := last.Time.Add(step); t.Before(end); t = t.Add(step) {
+		if !t.Before(first) {
+			result = append(result, flatOHLCVSlice(last.Close, t))
+		}
+	}
+
+	return result
+}
+
+// flatOHLCVSlice returns a slice at t without trades, at price.
+func flatOHLCVSlice(price Decimal, t time.Time) OHLCVSlice {
+	return OHLCVSlice{
+		Close: price,
+		High:  price,
+		Low:   price,
+		Open:  price,
+		Time:  t,
+	}
+}
@@ -0,0 +1,144 @@
+package qtrade
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_FetchOHLCVRange(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour * 2500)
+
+	var chunks [][2]time.Time
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/market/LTC_BTC/ohlcv/onehour",
+		func(req *http.Request) (*http.Response, error) {
+			query := req.URL.Query()
+			assert.Equal(t, strconv.Itoa(OHLCVLimit), query.Get("limit"))
+
+			chunkStart, _ := strconv.ParseInt(query.Get("start"), 10, 64)
+			chunkEnd, _ := strconv.ParseInt(query.Get("end"), 10, 64)
+			chunks = append(chunks, [2]time.Time{time.Unix(chunkStart, 0).UTC(), time.Unix(chunkEnd, 0).UTC()})
+
+			// every response also holds the slice before its chunk, and
+			// the one at the end of the whole range
+			result := new(GetOHLCVResult)
+			for t := time.Unix(chunkStart, 0).Add(-time.Hour); t.Unix() < chunkEnd; t = t.Add(time.Hour * 500) {
+				result.Data.Slices = append(result.Data.Slices, OHLCVSlice{Close: MustParseDecimal("0.02"), Time: t.UTC()})
+			}
+
+			result.Data.Slices = append(result.Data.Slices, OHLCVSlice{Time: end})
+
+			return httpmock.NewJsonResponse(200, result)
+		})
+
+	got, err := testClient.FetchOHLCVRange(context.Background(), LTC_BTC, OneHour, start, end)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, [][2]time.Time{
+		{start, start.Add(time.Hour * 1000)},
+		{start.Add(time.Hour * 1000), start.Add(time.Hour * 2000)},
+		{start.Add(time.Hour * 2000), end},
+	}, chunks)
+
+	var times []time.Time
+	for _, slice := range got {
+		times = append(times, slice.Time)
+	}
+
+	assert.Equal(t, []time.Time{
+		start.Add(time.Hour * 499),
+		start.Add(time.Hour * 999),
+		start.Add(time.Hour * 1499),
+		start.Add(time.Hour * 1999),
+		start.Add(time.Hour * 2499),
+	}, times)
+}
+
+func TestClient_FetchOHLCVRange_Invalid(t *testing.T) {
+	now := time.Now()
+
+	_, err := testClient.FetchOHLCVRange(context.Background(), LTC_BTC, Interval("fortnight"), now, now.Add(time.Hour))
+	assert.EqualError(t, err, `unknown interval "fortnight"`)
+
+	_, err = testClient.FetchOHLCVRange(context.Background(), LTC_BTC, OneHour, now, now)
+	assert.Error(t, err)
+}
+
+func TestFillOHLCVGaps(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	slices := []OHLCVSlice{
+		{Open: MustParseDecimal("1"), Close: MustParseDecimal("2"), High: MustParseDecimal("3"), Low: MustParseDecimal("1"), Volume: MustParseDecimal("10"), Time: start.Add(time.Minute * 10)},
+		{Open: MustParseDecimal("2"), Close: MustParseDecimal("4"), High: MustParseDecimal("4"), Low: MustParseDecimal("2"), Volume: MustParseDecimal("5"), Time: start.Add(time.Minute * 25)},
+		{Open: MustParseDecimal("4"), Close: MustParseDecimal("5"), High: MustParseDecimal("5"), Low: MustParseDecimal("4"), Volume: MustParseDecimal("1"), Time: start.Add(time.Minute * 30)},
+	}
+
+	flat := func(price string, minutes int) OHLCVSlice {
+		return OHLCVSlice{
+			Open:  MustParseDecimal(price),
+			Close: MustParseDecimal(price),
+			High:  MustParseDecimal(price),
+			Low:   MustParseDecimal(price),
+			Time:  start.Add(time.Minute * time.Duration(minutes)),
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		interval Interval
+		start    time.Time
+		end      time.Time
+		want     []OHLCVSlice
+	}{
+		{
+			name:     "edges and gaps",
+			interval: FiveMin,
+			start:    start,
+			end:      start.Add(time.Minute * 45),
+			want: []OHLCVSlice{
+				flat("1", 0), flat("1", 5), slices[0], flat("2", 15), flat("2", 20), slices[1], slices[2], flat("5", 35), flat("5", 40),
+			},
+		},
+		{
+			name:     "unaligned range",
+			interval: FiveMin,
+			start:    start.Add(time.Minute * 3),
+			end:      start.Add(time.Minute * 36),
+			want:     []OHLCVSlice{flat("1", 5), slices[0], flat("2", 15), flat("2", 20), slices[1], slices[2], flat("5", 35)},
+		},
+		{
+			name:     "range within the slices",
+			interval: FiveMin,
+			start:    start.Add(time.Minute * 10),
+			end:      start.Add(time.Minute * 35),
+			want:     []OHLCVSlice{slices[0], flat("2", 15), flat("2", 20), slices[1], slices[2]},
+		},
+		{
+			name:     "unknown interval",
+			interval: Interval(""),
+			start:    start,
+			end:      start.Add(time.Hour),
+			want:     slices,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, FillOHLCVGaps(slices, tc.interval, tc.start, tc.end))
+		})
+	}
+
+	assert.Empty(t, FillOHLCVGaps(nil, FiveMin, start, start.Add(time.Hour)))
+}
@@ -0,0 +1,74 @@
+package qtrade
+
+import (
+	"net/http"
+)
+
+// Option configures a Client created by NewClient.
+type Option func(*Client)
+
+// Logger receives diagnostic messages, such as retries. *log.Logger satisfies it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// WithHTTPClient makes the Client send requests through httpClient instead of
+// one built from Configuration.Timeout.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(client *Client) {
+		client.Client = httpClient
+	}
+}
+
+// WithTransport sets the RoundTripper used to send requests, for proxies,
+// mTLS or connection pool tuning. The HTTP client passed to WithHTTPClient, if
+// any, is copied rather than modified. If it was nil, a new one is built from
+// Configuration.Timeout.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(client *Client) {
+		httpClient := &http.Client{Timeout: client.Config.Timeout}
+		if client.Client != nil {
+			*httpClient = *client.Client
+		}
+
+		httpClient.Transport = transport
+		client.Client = httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(client *Client) {
+		client.userAgent = userAgent
+	}
+}
+
+// WithRetryPolicy replaces the BackoffPolicy built from Configuration.MaxRetries
+// and Configuration.Backoff. A nil policy disables retries.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(client *Client) {
+		client.RetryPolicy = policy
+	}
+}
+
+// WithLogger makes the Client report retries to logger.
+func WithLogger(logger Logger) Option {
+	return func(client *Client) {
+		client.logger = logger
+	}
+}
+
+// WithRegistry makes the Client use registry for market names and currency
+// precisions, so that markets listed after this package was released can be
+// traded. The registry is not refreshed by the Client; see Registry.Run.
+func WithRegistry(registry *Registry) Option {
+	return func(client *Client) {
+		client.registry = registry
+	}
+}
+
+func (client *Client) logf(format string, v ...interface{}) {
+	if client.logger != nil {
+		client.logger.Printf(format, v...)
+	}
+}
@@ -0,0 +1,133 @@
+package qtrade
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testConfig = Configuration{
+	HMACKeypair: "1:1111111111111111111111111111111111111111111111111111111111111111",
+	Endpoint:    "http://localhost",
+	Timeout:     time.Second * 10,
+	Backoff:     time.Millisecond,
+	MaxRetries:  1,
+}
+
+func userInfoTransport(status int, gotReq **http.Request, calls *int) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		*calls++
+		*gotReq = req
+
+		return &http.Response{
+			StatusCode: status,
+			Status:     http.StatusText(status),
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(userTestData)),
+		}, nil
+	})
+}
+
+func TestNewClient_Options(t *testing.T) {
+	t.Run("no options keeps configuration behaviour", func(t *testing.T) {
+		client, err := NewClient(testConfig)
+		if assert.NoError(t, err) {
+			assert.Equal(t, testConfig.Timeout, client.Client.Timeout)
+			assert.Equal(t, NewBackoffPolicy(1, time.Millisecond), client.RetryPolicy)
+		}
+	})
+
+	t.Run("WithHTTPClient", func(t *testing.T) {
+		httpClient := &http.Client{}
+
+		client, err := NewClient(testConfig, WithHTTPClient(httpClient))
+		if assert.NoError(t, err) {
+			assert.Same(t, httpClient, client.Client)
+		}
+	})
+
+	t.Run("WithTransport does not modify the given HTTP client", func(t *testing.T) {
+		var gotReq *http.Request
+		calls := 0
+		httpClient := &http.Client{}
+
+		client, err := NewClient(testConfig,
+			WithHTTPClient(httpClient),
+			WithTransport(userInfoTransport(http.StatusOK, &gotReq, &calls)))
+		if assert.NoError(t, err) {
+			_, err = client.GetUserInfo(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, 1, calls)
+			assert.Nil(t, httpClient.Transport)
+		}
+	})
+
+	t.Run("WithTransport after a nil HTTP client", func(t *testing.T) {
+		var gotReq *http.Request
+		calls := 0
+
+		client, err := NewClient(testConfig,
+			WithHTTPClient(nil),
+			WithTransport(userInfoTransport(http.StatusOK, &gotReq, &calls)))
+		if assert.NoError(t, err) {
+			assert.Equal(t, testConfig.Timeout, client.Client.Timeout)
+
+			_, err = client.GetUserInfo(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, 1, calls)
+		}
+	})
+
+	t.Run("WithUserAgent", func(t *testing.T) {
+		var gotReq *http.Request
+		calls := 0
+
+		client, err := NewClient(testConfig,
+			WithTransport(userInfoTransport(http.StatusOK, &gotReq, &calls)),
+			WithUserAgent("trading-bot/1.0"))
+		if assert.NoError(t, err) {
+			_, err = client.GetUserInfo(context.Background())
+			if assert.NoError(t, err) {
+				assert.Equal(t, "trading-bot/1.0", gotReq.Header.Get("User-Agent"))
+			}
+		}
+	})
+
+	t.Run("WithRetryPolicy and WithLogger", func(t *testing.T) {
+		var gotReq *http.Request
+		calls := 0
+		logs := new(bytes.Buffer)
+
+		client, err := NewClient(testConfig,
+			WithTransport(userInfoTransport(http.StatusServiceUnavailable, &gotReq, &calls)),
+			WithRetryPolicy(NewBackoffPolicy(3, time.Millisecond)),
+			WithLogger(log.New(logs, "", 0)))
+		if assert.NoError(t, err) {
+			_, err = client.GetUserInfo(context.Background())
+			assert.ErrorIs(t, err, ErrHTTPRetryable)
+			assert.Equal(t, 4, calls)
+			assert.Equal(t, 3, strings.Count(logs.String(), "qtrade: retrying GET /v1/user/me"))
+		}
+	})
+
+	t.Run("nil retry policy disables retries", func(t *testing.T) {
+		var gotReq *http.Request
+		calls := 0
+
+		client, err := NewClient(testConfig,
+			WithTransport(userInfoTransport(http.StatusServiceUnavailable, &gotReq, &calls)),
+			WithRetryPolicy(nil))
+		if assert.NoError(t, err) {
+			_, err = client.GetUserInfo(context.Background())
+			assert.ErrorIs(t, err, ErrHTTPRetryable)
+			assert.Equal(t, 1, calls)
+		}
+	})
+}
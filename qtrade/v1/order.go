@@ -0,0 +1,201 @@
+package qtrade
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// OrderRequest describes a limit order to place.
+type OrderRequest struct {
+	Market Market
+	// Side is BuyLimit or SellLimit.
+	Side   OrderType
+	Amount Decimal
+	Price  Decimal
+}
+
+// orderPrecision holds the decimal places of the amount and price of orders
+// in a market.
+type orderPrecision struct {
+	amount int
+	price  int
+}
+
+// PrepareOrder validates request and returns it with its amount and price
+// rounded to the precision of its market. Amounts are rounded down, so that
+// no more is traded than requested. Buy prices are rounded down and sell
+// prices up, so that the order is never worse than requested.
+//
+// Whether the market is tradable is checked here when the attached Registry
+// lists it. Other markets are checked by PlaceOrder, which can ask the API.
+//
+// The errors returned for invalid requests wrap ErrInvalidOrder.
+func (client *Client) PrepareOrder(request OrderRequest) (OrderRequest, error) {
+	prepared, _, err := client.prepareOrder(request)
+
+	return prepared, err
+}
+
+func (client *Client) prepareOrder(request OrderRequest) (OrderRequest, orderPrecision, error) {
+	name := client.marketName(request.Market)
+
+	if request.Side != BuyLimit && request.Side != SellLimit {
+		return request, orderPrecision{}, errors.Wrapf(ErrInvalidOrder, "unknown order side %q", request.Side)
+	}
+
+	if request.Amount.Sign() <= 0 {
+		return request, orderPrecision{}, errors.Wrapf(ErrInvalidOrder, "amount %v for %v is not positive", request.Amount, name)
+	}
+
+	if request.Price.Sign() <= 0 {
+		return request, orderPrecision{}, errors.Wrapf(ErrInvalidOrder, "price %v for %v is not positive", request.Price, name)
+	}
+
+	precision, err := client.orderPrecision(request.Market)
+	if err != nil {
+		return request, orderPrecision{}, err
+	}
+
+	prepared := request
+	prepared.Amount = request.Amount.RoundDown(precision.amount)
+
+	if request.Side == BuyLimit {
+		prepared.Price = request.Price.RoundDown(precision.price)
+	} else {
+		prepared.Price = request.Price.RoundUp(precision.price)
+	}
+
+	if prepared.Amount.IsZero() {
+		return request, orderPrecision{}, errors.Wrapf(ErrInvalidOrder,
+			"amount %v for %v is below the precision of %v decimal places", request.Amount, name, precision.amount)
+	}
+
+	if prepared.Price.IsZero() {
+		return request, orderPrecision{}, errors.Wrapf(ErrInvalidOrder,
+			"price %v for %v is below the precision of %v decimal places", request.Price, name, precision.price)
+	}
+
+	return prepared, precision, nil
+}
+
+// orderPrecision returns the precision of orders in market, from the attached
+// registry if it lists the market, or from the tables of this package. It
+// fails if the registry lists the market as not tradable, or if its precision
+// is not known.
+func (client *Client) orderPrecision(market Market) (orderPrecision, error) {
+	name := client.marketName(market)
+
+	if client.registry != nil {
+		if info, ok := client.registry.Market(market); ok {
+			if !info.CanTrade {
+				return orderPrecision{}, errors.Wrapf(ErrInvalidOrder, "market %v is not tradable", name)
+			}
+
+			if info.MarketPrecision == UnknownPrecision {
+				return orderPrecision{}, errors.Wrapf(ErrInvalidOrder, "unknown precision of %v", info.MarketCurrency)
+			}
+
+			if info.BasePrecision == UnknownPrecision {
+				return orderPrecision{}, errors.Wrapf(ErrInvalidOrder, "unknown precision of %v", info.BaseCurrency)
+			}
+
+			return orderPrecision{amount: info.MarketPrecision, price: info.BasePrecision}, nil
+		}
+	}
+
+	marketCurrency, baseCurrency := market.MarketCurrency(), market.BaseCurrency()
+	if marketCurrency == "" || baseCurrency == "" {
+		return orderPrecision{}, errors.Wrapf(ErrInvalidOrder, "unknown market %d", int(market))
+	}
+
+	amountPlaces, ok := client.lookupPrecision(marketCurrency)
+	if !ok {
+		return orderPrecision{}, errors.Wrapf(ErrInvalidOrder, "unknown precision of %v", marketCurrency)
+	}
+
+	pricePlaces, ok := client.lookupPrecision(baseCurrency)
+	if !ok {
+		return orderPrecision{}, errors.Wrapf(ErrInvalidOrder, "unknown precision of %v", baseCurrency)
+	}
+
+	return orderPrecision{amount: amountPlaces, price: pricePlaces}, nil
+}
+
+// PlaceOrder validates and rounds request with PrepareOrder, then places it.
+// Invalid requests are not sent to the API. Markets that no attached Registry
+// lists are read with GetMarket the first time they are traded, and rejected
+// if they are not tradable.
+func (client *Client) PlaceOrder(ctx context.Context, request OrderRequest) (*Order, error) {
+	request, precision, err := client.prepareOrder(request)
+	if err != nil {
+		return nil, err
+	}
+
+	err = client.checkTradable(ctx, request.Market)
+	if err != nil {
+		return nil, err
+	}
+
+	side := "sell"
+	if request.Side == BuyLimit {
+		side = "buy"
+	}
+
+	result := new(CreateOrderResult)
+
+	err = client.doRequest(ctx, apiRequest{
+		method: "POST",
+		path:   "/v1/user/" + string(request.Side),
+		body: map[string]interface{}{
+			"amount":    request.Amount.StringFixed(precision.amount),
+			"market_id": int(request.Market),
+			"price":     request.Price.StringFixed(precision.price),
+		},
+	}, result)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create "+side+" order for "+client.marketName(request.Market))
+	}
+
+	return &result.Data.Order, nil
+}
+
+// checkTradable fails with an error wrapping ErrInvalidOrder if market is not
+// tradable. Markets listed by the attached registry were already checked by
+// prepareOrder. Others are read with GetMarket until it lists them as
+// tradable, so that a halted market is read again on its next order.
+func (client *Client) checkTradable(ctx context.Context, market Market) error {
+	if client.registry != nil {
+		if _, ok := client.registry.Market(market); ok {
+			return nil
+		}
+	}
+
+	client.tradableMu.Lock()
+	tradable := client.tradable[market]
+	client.tradableMu.Unlock()
+
+	if tradable {
+		return nil
+	}
+
+	data, err := client.GetMarket(ctx, market)
+	if err != nil {
+		return errors.Wrap(err, "failed to check that "+client.marketName(market)+" is tradable")
+	}
+
+	if !data.Market.CanTrade {
+		return errors.Wrapf(ErrInvalidOrder, "market %v is not tradable", client.marketName(market))
+	}
+
+	client.tradableMu.Lock()
+	defer client.tradableMu.Unlock()
+
+	if client.tradable == nil {
+		client.tradable = map[Market]bool{}
+	}
+
+	client.tradable[market] = true
+
+	return nil
+}
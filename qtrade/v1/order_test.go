@@ -0,0 +1,213 @@
+package qtrade
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// tradableMarketData is a GetMarket response listing LTC_BTC as tradable.
+const tradableMarketData = `{"data": {"market": {"id": 1, "market_currency": "LTC", "base_currency": "BTC", "can_trade": true}, "recent_trades": []}}`
+
+// registerTradableMarket answers the GetMarket call that PlaceOrder makes
+// before trading LTC_BTC for the first time.
+func registerTradableMarket() {
+	httpmock.RegisterResponder("GET", "http://localhost/v1/market/LTC_BTC",
+		httpmock.NewStringResponder(200, tradableMarketData))
+}
+
+func TestClient_PrepareOrder(t *testing.T) {
+	registry := NewRegistry()
+	registry.Load(&CommonData{
+		Currencies: newListingCommonData.Currencies,
+		Markets: []MarketData{
+			newListingCommonData.Markets[0],
+			{ID: Market(998), MarketCurrency: "NEW", BaseCurrency: BTC, CanView: true},
+			{ID: Market(997), MarketCurrency: "ODD", BaseCurrency: BTC, CanTrade: true, CanView: true},
+		},
+	})
+
+	registryClient, err := NewClient(testConfig, WithRegistry(registry))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	testCases := []struct {
+		name    string
+		client  *Client
+		request OrderRequest
+		want    OrderRequest
+		wantErr string
+	}{
+		{
+			name:    "buy rounds down",
+			client:  testClient,
+			request: OrderRequest{Market: LTC_BTC, Side: BuyLimit, Amount: MustParseDecimal("1.123456789"), Price: MustParseDecimal("0.123456789")},
+			want:    OrderRequest{Market: LTC_BTC, Side: BuyLimit, Amount: MustParseDecimal("1.12345678"), Price: MustParseDecimal("0.12345678")},
+		},
+		{
+			name:    "sell rounds price up",
+			client:  testClient,
+			request: OrderRequest{Market: LTC_BTC, Side: SellLimit, Amount: MustParseDecimal("1.123456789"), Price: MustParseDecimal("0.123456781")},
+			want:    OrderRequest{Market: LTC_BTC, Side: SellLimit, Amount: MustParseDecimal("1.12345678"), Price: MustParseDecimal("0.12345679")},
+		},
+		{
+			name:    "registry precision",
+			client:  registryClient,
+			request: OrderRequest{Market: Market(999), Side: BuyLimit, Amount: MustParseDecimal("12.34567"), Price: MustParseDecimal("0.00001234")},
+			want:    OrderRequest{Market: Market(999), Side: BuyLimit, Amount: MustParseDecimal("12.3456"), Price: MustParseDecimal("0.00001234")},
+		},
+		{
+			name:    "unknown side",
+			client:  testClient,
+			request: OrderRequest{Market: LTC_BTC, Side: "stop_loss", Amount: DecimalFromInt(1), Price: DecimalFromInt(1)},
+			wantErr: `unknown order side "stop_loss": invalid order`,
+		},
+		{
+			name:    "zero amount",
+			client:  testClient,
+			request: OrderRequest{Market: LTC_BTC, Side: BuyLimit, Price: DecimalFromInt(1)},
+			wantErr: "amount 0 for LTC_BTC is not positive: invalid order",
+		},
+		{
+			name:    "negative price",
+			client:  testClient,
+			request: OrderRequest{Market: LTC_BTC, Side: SellLimit, Amount: DecimalFromInt(1), Price: MustParseDecimal("-0.1")},
+			wantErr: "price -0.1 for LTC_BTC is not positive: invalid order",
+		},
+		{
+			name:    "amount below precision",
+			client:  testClient,
+			request: OrderRequest{Market: LTC_BTC, Side: BuyLimit, Amount: MustParseDecimal("0.000000001"), Price: DecimalFromInt(1)},
+			wantErr: "amount 0.000000001 for LTC_BTC is below the precision of 8 decimal places: invalid order",
+		},
+		{
+			name:    "buy price below precision",
+			client:  testClient,
+			request: OrderRequest{Market: LTC_BTC, Side: BuyLimit, Amount: DecimalFromInt(1), Price: MustParseDecimal("0.000000001")},
+			wantErr: "price 0.000000001 for LTC_BTC is below the precision of 8 decimal places: invalid order",
+		},
+		{
+			name:    "sell price below precision",
+			client:  testClient,
+			request: OrderRequest{Market: LTC_BTC, Side: SellLimit, Amount: DecimalFromInt(1), Price: MustParseDecimal("0.000000001")},
+			want:    OrderRequest{Market: LTC_BTC, Side: SellLimit, Amount: DecimalFromInt(1), Price: MustParseDecimal("0.00000001")},
+		},
+		{
+			name:    "unknown market",
+			client:  testClient,
+			request: OrderRequest{Market: Market(999), Side: BuyLimit, Amount: DecimalFromInt(1), Price: DecimalFromInt(1)},
+			wantErr: "unknown market 999: invalid order",
+		},
+		{
+			name:    "market not tradable",
+			client:  registryClient,
+			request: OrderRequest{Market: Market(998), Side: BuyLimit, Amount: DecimalFromInt(1), Price: DecimalFromInt(1)},
+			wantErr: "market NEW_BTC is not tradable: invalid order",
+		},
+		{
+			name:    "registry precision unknown",
+			client:  registryClient,
+			request: OrderRequest{Market: Market(997), Side: BuyLimit, Amount: MustParseDecimal("1.5"), Price: DecimalFromInt(1)},
+			wantErr: "unknown precision of ODD: invalid order",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.client.PrepareOrder(tc.request)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				assert.True(t, IsInvalidOrder(err))
+
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestClient_PlaceOrder(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	registerTradableMarket()
+
+	httpmock.RegisterResponder("POST", "http://localhost/v1/user/sell_limit",
+		func(req *http.Request) (*http.Response, error) {
+			body, _ := ioutil.ReadAll(req.Body)
+			assert.JSONEq(t, `{"amount":"1.50000000","market_id":1,"price":"0.01234568"}`, string(body))
+
+			return httpmock.NewStringResponse(200, sellLimitData), nil
+		})
+
+	order, err := testClient.PlaceOrder(context.Background(), OrderRequest{
+		Market: LTC_BTC,
+		Side:   SellLimit,
+		Amount: MustParseDecimal("1.5"),
+		Price:  MustParseDecimal("0.012345671"),
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 13253, order.ID)
+	}
+
+	_, err = testClient.PlaceOrder(context.Background(), OrderRequest{
+		Market: LTC_BTC,
+		Side:   SellLimit,
+		Amount: MustParseDecimal("-1"),
+		Price:  MustParseDecimal("0.01"),
+	})
+	assert.True(t, IsInvalidOrder(err))
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["POST http://localhost/v1/user/sell_limit"])
+}
+
+func TestClient_PlaceOrder_Tradable(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	client, err := NewClient(testConfig)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	registerTradableMarket()
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/market/MMO_BTC",
+		httpmock.NewStringResponder(200, `{"data": {"market": {"id": 2, "market_currency": "MMO", "base_currency": "BTC", "can_trade": false}}}`))
+
+	httpmock.RegisterResponder("POST", "http://localhost/v1/user/buy_limit",
+		httpmock.NewStringResponder(200, buyLimitData))
+
+	for i := 0; i < 2; i++ {
+		_, err = client.PlaceOrder(context.Background(), OrderRequest{
+			Market: LTC_BTC,
+			Side:   BuyLimit,
+			Amount: MustParseDecimal("1"),
+			Price:  MustParseDecimal("0.01"),
+		})
+		assert.NoError(t, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err = client.PlaceOrder(context.Background(), OrderRequest{
+			Market: MMO_BTC,
+			Side:   BuyLimit,
+			Amount: MustParseDecimal("1"),
+			Price:  MustParseDecimal("0.01"),
+		})
+		assert.EqualError(t, err, "market MMO_BTC is not tradable: invalid order")
+	}
+
+	// tradable markets are read once, others on every order
+	calls := httpmock.GetCallCountInfo()
+	assert.Equal(t, 1, calls["GET http://localhost/v1/market/LTC_BTC"])
+	assert.Equal(t, 2, calls["GET http://localhost/v1/market/MMO_BTC"])
+	assert.Equal(t, 2, calls["POST http://localhost/v1/user/buy_limit"])
+}
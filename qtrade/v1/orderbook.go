@@ -0,0 +1,250 @@
+package qtrade
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// OrderbookLevel is the total amount offered at one price.
+type OrderbookLevel struct {
+	Price  Decimal `json:"price"`
+	Amount Decimal `json:"amount"`
+}
+
+// OrderbookLevels is one side of an orderbook, best price first: highest
+// first for bids and lowest first for asks.
+type OrderbookLevels []OrderbookLevel
+
+// NewOrderbook returns an orderbook holding the given levels, which may be in
+// any order. Levels at the same price are merged, and the Buy and Sell maps
+// are filled in from them.
+func NewOrderbook(bids, asks []OrderbookLevel, lastChange int) *Orderbook {
+	book := &Orderbook{
+		Bids:       mergeLevels(bids, true),
+		Asks:       mergeLevels(asks, false),
+		LastChange: lastChange,
+	}
+
+	book.Buy = book.Bids.floatMap()
+	book.Sell = book.Asks.floatMap()
+
+	return book
+}
+
+// mergeLevels sorts levels, descending if desc is set, and sums the amounts
+// of levels with the same price.
+func mergeLevels(levels []OrderbookLevel, desc bool) OrderbookLevels {
+	sorted := make(OrderbookLevels, 0, len(levels))
+	sorted = append(sorted, levels...)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if desc {
+			return sorted[i].Price.Cmp(sorted[j].Price) > 0
+		}
+
+		return sorted[i].Price.Cmp(sorted[j].Price) < 0
+	})
+
+	merged := sorted[:0]
+
+	for _, level := range sorted {
+		if n := len(merged); n > 0 && merged[n-1].Price == level.Price {
+			merged[n-1].Amount = merged[n-1].Amount.Add(level.Amount)
+			continue
+		}
+
+		merged = append(merged, level)
+	}
+
+	return merged
+}
+
+// parseOrderbookLevels parses one side of an orderbook as sent by the API,
+// which maps prices to amounts.
+func parseOrderbookLevels(levels map[string]string) ([]OrderbookLevel, error) {
+	parsed := make([]OrderbookLevel, 0, len(levels))
+
+	for price, amount := range levels {
+		level := OrderbookLevel{}
+
+		var err error
+
+		level.Price, err = ParseDecimal(price)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse orderbook price")
+		}
+
+		level.Amount, err = ParseDecimal(amount)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse orderbook amount")
+		}
+
+		parsed = append(parsed, level)
+	}
+
+	return parsed, nil
+}
+
+func (levels OrderbookLevels) floatMap() map[float64]float64 {
+	m := make(map[float64]float64, len(levels))
+	for _, level := range levels {
+		m[level.Price.Float64()] += level.Amount.Float64()
+	}
+
+	return m
+}
+
+// Total returns the amount offered at all levels.
+func (levels OrderbookLevels) Total() Decimal {
+	var total Decimal
+	for _, level := range levels {
+		total = total.Add(level.Amount)
+	}
+
+	return total
+}
+
+// Cumulative returns the levels with each amount replaced by the amount
+// offered at that price or better.
+func (levels OrderbookLevels) Cumulative() OrderbookLevels {
+	cumulative := make(OrderbookLevels, len(levels))
+
+	var total Decimal
+
+	for i, level := range levels {
+		total = total.Add(level.Amount)
+		cumulative[i] = OrderbookLevel{Price: level.Price, Amount: total}
+	}
+
+	return cumulative
+}
+
+// FillCost returns the value, in the base currency, of taking amount from the
+// levels best price first. It returns false if the levels hold less than
+// amount.
+func (levels OrderbookLevels) FillCost(amount Decimal) (Decimal, bool) {
+	var cost Decimal
+
+	remaining := amount
+
+	for _, level := range levels {
+		if remaining.Sign() <= 0 {
+			break
+		}
+
+		taken := level.Amount
+		if taken.Cmp(remaining) > 0 {
+			taken = remaining
+		}
+
+		cost = cost.Add(taken.Mul(level.Price))
+		remaining = remaining.Sub(taken)
+	}
+
+	if remaining.Sign() > 0 {
+		return Decimal{}, false
+	}
+
+	return cost, true
+}
+
+// VWAP returns the average price, rounded to places, of taking amount from
+// the levels. It returns false if amount is not positive or the levels hold
+// less than amount.
+func (levels OrderbookLevels) VWAP(amount Decimal, places int) (Decimal, bool) {
+	if amount.Sign() <= 0 {
+		return Decimal{}, false
+	}
+
+	cost, ok := levels.FillCost(amount)
+	if !ok {
+		return Decimal{}, false
+	}
+
+	return cost.Div(amount, places), true
+}
+
+// BestBid returns the highest bid, or false if there are no bids.
+func (book *Orderbook) BestBid() (OrderbookLevel, bool) {
+	if len(book.Bids) == 0 {
+		return OrderbookLevel{}, false
+	}
+
+	return book.Bids[0], true
+}
+
+// BestAsk returns the lowest ask, or false if there are no asks.
+func (book *Orderbook) BestAsk() (OrderbookLevel, bool) {
+	if len(book.Asks) == 0 {
+		return OrderbookLevel{}, false
+	}
+
+	return book.Asks[0], true
+}
+
+// Spread returns the best ask minus the best bid, or false if either side is
+// empty.
+func (book *Orderbook) Spread() (Decimal, bool) {
+	bid, ask, ok := book.top()
+	if !ok {
+		return Decimal{}, false
+	}
+
+	return ask.Price.Sub(bid.Price), true
+}
+
+// Mid returns the exact midpoint of the best bid and ask, or false if either
+// side is empty.
+func (book *Orderbook) Mid() (Decimal, bool) {
+	bid, ask, ok := book.top()
+	if !ok {
+		return Decimal{}, false
+	}
+
+	places := bid.Price.Places()
+	if ask.Price.Places() > places {
+		places = ask.Price.Places()
+	}
+
+	return bid.Price.Add(ask.Price).Div(DecimalFromInt(2), places+1), true
+}
+
+// Imbalance returns (bids - asks) / (bids + asks) for the amounts offered at
+// the best depth levels of each side, or at all levels if depth is 0. It
+// ranges from -1, when there are only asks, to 1, when there are only bids,
+// and is 0 for an empty book.
+func (book *Orderbook) Imbalance(depth int) float64 {
+	bids, asks := book.Bids, book.Asks
+
+	if depth > 0 && len(bids) > depth {
+		bids = bids[:depth]
+	}
+
+	if depth > 0 && len(asks) > depth {
+		asks = asks[:depth]
+	}
+
+	bidTotal, askTotal := bids.Total(), asks.Total()
+
+	total := bidTotal.Add(askTotal)
+	if total.IsZero() {
+		return 0
+	}
+
+	return bidTotal.Sub(askTotal).Float64() / total.Float64()
+}
+
+func (book *Orderbook) top() (OrderbookLevel, OrderbookLevel, bool) {
+	bid, ok := book.BestBid()
+	if !ok {
+		return OrderbookLevel{}, OrderbookLevel{}, false
+	}
+
+	ask, ok := book.BestAsk()
+	if !ok {
+		return OrderbookLevel{}, OrderbookLevel{}, false
+	}
+
+	return bid, ask, true
+}
@@ -0,0 +1,94 @@
+package qtrade
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func level(price, amount string) OrderbookLevel {
+	return OrderbookLevel{Price: MustParseDecimal(price), Amount: MustParseDecimal(amount)}
+}
+
+func TestNewOrderbook(t *testing.T) {
+	bids, err := parseOrderbookLevels(map[string]string{"0.0001": "1", "0.00010000": "2", "0.00009": "5"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	book := NewOrderbook(bids, []OrderbookLevel{level("0.0003", "1"), level("0.0002", "4")}, 7)
+
+	assert.Equal(t, OrderbookLevels{level("0.0001", "3"), level("0.00009", "5")}, book.Bids)
+	assert.Equal(t, OrderbookLevels{level("0.0002", "4"), level("0.0003", "1")}, book.Asks)
+	assert.Equal(t, map[float64]float64{0.0001: 3, 0.00009: 5}, book.Buy)
+	assert.Equal(t, map[float64]float64{0.0002: 4, 0.0003: 1}, book.Sell)
+	assert.Equal(t, 7, book.LastChange)
+
+	_, err = parseOrderbookLevels(map[string]string{"one": "1"})
+	assert.Error(t, err)
+}
+
+func TestOrderbook_Analytics(t *testing.T) {
+	book := NewOrderbook(
+		[]OrderbookLevel{level("0.99", "1"), level("0.98", "3")},
+		[]OrderbookLevel{level("1.01", "2"), level("1.05", "2"), level("1.1", "6")},
+		0)
+
+	bid, ok := book.BestBid()
+	if assert.True(t, ok) {
+		assert.Equal(t, level("0.99", "1"), bid)
+	}
+
+	ask, ok := book.BestAsk()
+	if assert.True(t, ok) {
+		assert.Equal(t, level("1.01", "2"), ask)
+	}
+
+	spread, ok := book.Spread()
+	if assert.True(t, ok) {
+		assert.Equal(t, MustParseDecimal("0.02"), spread)
+	}
+
+	mid, ok := book.Mid()
+	if assert.True(t, ok) {
+		assert.Equal(t, MustParseDecimal("1"), mid)
+	}
+
+	assert.Equal(t, OrderbookLevels{level("1.01", "2"), level("1.05", "4"), level("1.1", "10")}, book.Asks.Cumulative())
+	assert.Equal(t, MustParseDecimal("4"), book.Bids.Total())
+
+	cost, ok := book.Asks.FillCost(MustParseDecimal("3"))
+	if assert.True(t, ok) {
+		assert.Equal(t, MustParseDecimal("3.07"), cost)
+	}
+
+	vwap, ok := book.Asks.VWAP(MustParseDecimal("3"), 4)
+	if assert.True(t, ok) {
+		assert.Equal(t, MustParseDecimal("1.0233"), vwap)
+	}
+
+	_, ok = book.Asks.FillCost(MustParseDecimal("10.5"))
+	assert.False(t, ok)
+
+	_, ok = book.Asks.VWAP(Decimal{}, 4)
+	assert.False(t, ok)
+
+	assert.InDelta(t, -1.0/3, book.Imbalance(1), 1e-9)
+	assert.InDelta(t, -6.0/14, book.Imbalance(0), 1e-9)
+}
+
+func TestOrderbook_Empty(t *testing.T) {
+	book := NewOrderbook(nil, []OrderbookLevel{level("1", "1")}, 0)
+
+	_, ok := book.BestBid()
+	assert.False(t, ok)
+
+	_, ok = book.Spread()
+	assert.False(t, ok)
+
+	_, ok = book.Mid()
+	assert.False(t, ok)
+
+	assert.Equal(t, -1.0, book.Imbalance(0))
+	assert.Equal(t, 0.0, NewOrderbook(nil, nil, 0).Imbalance(0))
+}
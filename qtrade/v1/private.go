@@ -1,11 +1,8 @@
 package qtrade
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"strconv"
 
 	"github.com/pkg/errors"
@@ -14,12 +11,7 @@ import (
 func (client *Client) GetUserInfo(ctx context.Context) (*UserInfo, error) {
 	result := new(GetUserInfoResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", client.Config.Endpoint+"/v1/user/me", nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get user info")
-	}
-
-	err = client.doRequest(req, result, nil)
+	err := client.doRequest(ctx, apiRequest{method: "GET", path: "/v1/user/me"}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get user info")
 	}
@@ -30,12 +22,7 @@ func (client *Client) GetUserInfo(ctx context.Context) (*UserInfo, error) {
 func (client *Client) GetBalances(ctx context.Context, params map[string]string) ([]Balance, error) {
 	result := new(GetBalancesResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", client.Config.Endpoint+"/v1/user/balances", nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get balances")
-	}
-
-	err = client.doRequest(req, result, params)
+	err := client.doRequest(ctx, apiRequest{method: "GET", path: "/v1/user/balances", params: params}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get balances")
 	}
@@ -46,16 +33,13 @@ func (client *Client) GetBalances(ctx context.Context, params map[string]string)
 func (client *Client) GetUserMarket(ctx context.Context, market Market, params map[string]string) (*UserMarketData, error) {
 	result := new(GetUserMarketResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET",
-		fmt.Sprintf("%s/v1/user/market/%s", client.Config.Endpoint, market.String()),
-		nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get user market view for "+market.String())
-	}
-
-	err = client.doRequest(req, result, params)
+	err := client.doRequest(ctx, apiRequest{
+		method: "GET",
+		path:   "/v1/user/market/" + client.marketName(market),
+		params: params,
+	}, result)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get user market view for "+market.String())
+		return nil, errors.Wrap(err, "failed to get user market view for "+client.marketName(market))
 	}
 
 	return &result.Data, nil
@@ -64,12 +48,7 @@ func (client *Client) GetUserMarket(ctx context.Context, market Market, params m
 func (client *Client) GetOrders(ctx context.Context, params map[string]string) ([]Order, error) {
 	result := new(GetOrdersResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", client.Config.Endpoint+"/v1/user/orders", nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get orders")
-	}
-
-	err = client.doRequest(req, result, params)
+	err := client.doRequest(ctx, apiRequest{method: "GET", path: "/v1/user/orders", params: params}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get orders")
 	}
@@ -80,14 +59,7 @@ func (client *Client) GetOrders(ctx context.Context, params map[string]string) (
 func (client *Client) GetOrder(ctx context.Context, id int) (*Order, error) {
 	result := new(GetOrderResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET",
-		fmt.Sprintf("%s/v1/user/order/%v", client.Config.Endpoint, id),
-		nil)
-	if err != nil {
-		return nil, errors.Wrap(err, fmt.Sprintf("failed to get order %v", id))
-	}
-
-	err = client.doRequest(req, result, nil)
+	err := client.doRequest(ctx, apiRequest{method: "GET", path: fmt.Sprintf("/v1/user/order/%v", id)}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("failed to get order %v", id))
 	}
@@ -98,12 +70,7 @@ func (client *Client) GetOrder(ctx context.Context, id int) (*Order, error) {
 func (client *Client) GetTrades(ctx context.Context, params map[string]string) ([]PrivateTrade, error) {
 	result := new(GetTradesResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", client.Config.Endpoint+"/v1/user/trades", nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get trades")
-	}
-
-	err = client.doRequest(req, result, params)
+	err := client.doRequest(ctx, apiRequest{method: "GET", path: "/v1/user/trades", params: params}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get trades")
 	}
@@ -112,75 +79,61 @@ func (client *Client) GetTrades(ctx context.Context, params map[string]string) (
 }
 
 func (client *Client) CancelOrder(ctx context.Context, id int) error {
-	body := map[string]interface{}{
-		"id": id,
-	}
-
-	bodyBytes, err := json.Marshal(body)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequestWithContext(ctx,
-		"POST",
-		client.Config.Endpoint+"/v1/user/cancel_order",
-		bytes.NewReader(bodyBytes))
+	err := client.doRequest(ctx, apiRequest{
+		method: "POST",
+		path:   "/v1/user/cancel_order",
+		body: map[string]interface{}{
+			"id": id,
+		},
+		idempotent: true,
+	}, nil)
 	if err != nil {
 		return errors.Wrap(err, fmt.Sprintf("failed to cancel order %v", id))
 	}
 
-	auth, timestamp, err := client.generateHMAC(req)
-	if err != nil {
-		return errors.Wrap(err, fmt.Sprintf("failed to cancel order %v", id))
-	}
-
-	req.Header.Set("Authorization", auth)
-	req.Header.Set("HMAC-Timestamp", timestamp)
+	return nil
+}
 
-	resp, err := client.Client.Do(req)
-	if err != nil {
-		return errors.Wrap(err, fmt.Sprintf("failed to cancel order %v", id))
+// Withdraw sends amount of currency to address. The amount is never rounded:
+// it must be positive and fit the precision of currency, which must be known,
+// otherwise an error wrapping ErrInvalidWithdrawal is returned and nothing is
+// sent.
+func (client *Client) Withdraw(ctx context.Context, address string, amount Decimal, currency Currency) (*WithdrawData, error) {
+	places, ok := client.lookupPrecision(currency)
+	if !ok {
+		return nil, errors.Wrapf(ErrInvalidWithdrawal, "unknown precision of %v", currency)
 	}
 
-	return checkForError(resp)
-}
-
-func (client *Client) Withdraw(ctx context.Context, address string, amount float64, currency Currency) (*WithdrawData, error) {
-	body := map[string]interface{}{
-		"address":  address,
-		"amount":   strconv.FormatFloat(amount, 'f', CurrencyDecimalPlaces[currency], 64),
-		"currency": currency,
+	if amount.Sign() <= 0 {
+		return nil, errors.Wrapf(ErrInvalidWithdrawal, "amount %v of %v is not positive", amount, currency)
 	}
 
-	bodyBytes, err := json.Marshal(body)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to withdraw "+string(currency))
+	if amount.RoundDown(places).Cmp(amount) != 0 {
+		return nil, errors.Wrapf(ErrInvalidWithdrawal, "amount %v has more than the %v decimal places of %v", amount, places, currency)
 	}
 
 	result := new(WithdrawResult)
 
-	req, err := http.NewRequestWithContext(ctx,
-		"POST",
-		client.Config.Endpoint+"/v1/user/withdraw",
-		bytes.NewReader(bodyBytes))
+	err := client.doRequest(ctx, apiRequest{
+		method: "POST",
+		path:   "/v1/user/withdraw",
+		body: map[string]interface{}{
+			"address":  address,
+			"amount":   amount.StringFixed(places),
+			"currency": currency,
+		},
+	}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to withdraw "+string(currency))
 	}
 
-	return &result.Data, client.doRequest(req, result, nil)
+	return &result.Data, nil
 }
 
 func (client *Client) GetWithdrawDetails(ctx context.Context, id int) (*WithdrawDetails, error) {
 	result := new(GetWithdrawDetailsResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET",
-		client.Config.Endpoint+"/v1/user/withdraw/"+strconv.Itoa(id),
-		nil)
-	if err != nil {
-		return nil, errors.Wrap(err, fmt.Sprintf("failed to get details for withdrawal %v", id))
-	}
-
-	err = client.doRequest(req, result, nil)
+	err := client.doRequest(ctx, apiRequest{method: "GET", path: "/v1/user/withdraw/" + strconv.Itoa(id)}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("failed to get details for withdrawal %v", id))
 	}
@@ -191,14 +144,7 @@ func (client *Client) GetWithdrawDetails(ctx context.Context, id int) (*Withdraw
 func (client *Client) GetWithdrawHistory(ctx context.Context, params map[string]string) ([]WithdrawDetails, error) {
 	result := new(GetWithdrawHistoryResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET",
-		client.Config.Endpoint+"/v1/user/withdraws",
-		nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get withdraw history")
-	}
-
-	err = client.doRequest(req, result, params)
+	err := client.doRequest(ctx, apiRequest{method: "GET", path: "/v1/user/withdraws", params: params}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get withdraw history")
 	}
@@ -209,14 +155,7 @@ func (client *Client) GetWithdrawHistory(ctx context.Context, params map[string]
 func (client *Client) GetDeposit(ctx context.Context, id string) ([]DepositDetails, error) {
 	result := new(GetDepositResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET",
-		client.Config.Endpoint+"/v1/user/deposit/"+id,
-		nil)
-	if err != nil {
-		return nil, errors.Wrap(err, fmt.Sprintf("failed to get deposit %v", id))
-	}
-
-	err = client.doRequest(req, result, nil)
+	err := client.doRequest(ctx, apiRequest{method: "GET", path: "/v1/user/deposit/" + id}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("failed to get deposit %v", id))
 	}
@@ -227,14 +166,7 @@ func (client *Client) GetDeposit(ctx context.Context, id string) ([]DepositDetai
 func (client *Client) GetDepositHistory(ctx context.Context, params map[string]string) ([]DepositDetails, error) {
 	result := new(GetDepositHistoryResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET",
-		client.Config.Endpoint+"/v1/user/deposits",
-		nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get deposit history")
-	}
-
-	err = client.doRequest(req, result, params)
+	err := client.doRequest(ctx, apiRequest{method: "GET", path: "/v1/user/deposits", params: params}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get deposit history")
 	}
@@ -245,14 +177,11 @@ func (client *Client) GetDepositHistory(ctx context.Context, params map[string]s
 func (client *Client) GetDepositAddress(ctx context.Context, currency Currency) (*DepositAddressData, error) {
 	result := new(GetDepositAddressResult)
 
-	req, err := http.NewRequestWithContext(ctx, "POST",
-		client.Config.Endpoint+"/v1/user/deposit_address/"+string(currency),
-		nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get deposit address for "+string(currency))
-	}
-
-	err = client.doRequest(req, result, nil)
+	err := client.doRequest(ctx, apiRequest{
+		method:     "POST",
+		path:       "/v1/user/deposit_address/" + string(currency),
+		idempotent: true,
+	}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get deposit address for "+string(currency))
 	}
@@ -263,14 +192,7 @@ func (client *Client) GetDepositAddress(ctx context.Context, currency Currency)
 func (client *Client) GetTransfers(ctx context.Context, params map[string]string) ([]Transfer, error) {
 	result := new(GetTransfersResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET",
-		client.Config.Endpoint+"/v1/user/transfers",
-		nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get transfers")
-	}
-
-	err = client.doRequest(req, result, params)
+	err := client.doRequest(ctx, apiRequest{method: "GET", path: "/v1/user/transfers", params: params}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get transfers")
 	}
@@ -278,62 +200,12 @@ func (client *Client) GetTransfers(ctx context.Context, params map[string]string
 	return result.Data.Transfers, nil
 }
 
-func (client *Client) CreateSellLimit(ctx context.Context, amount float64, market Market, price float64) (*Order, error) {
-	result := new(CreateOrderResult)
-
-	body := map[string]interface{}{
-		"amount":    strconv.FormatFloat(amount, 'f', CurrencyDecimalPlaces[market.MarketCurrency()], 64),
-		"market_id": market,
-		"price":     strconv.FormatFloat(price, 'f', CurrencyDecimalPlaces[market.BaseCurrency()], 64),
-	}
-
-	bodyBytes, err := json.Marshal(body)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create sell order for "+market.String())
-	}
-
-	req, err := http.NewRequestWithContext(ctx,
-		"POST",
-		client.Config.Endpoint+"/v1/user/sell_limit",
-		bytes.NewReader(bodyBytes))
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create sell order")
-	}
-
-	err = client.doRequest(req, result, nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create sell order for "+market.String())
-	}
-
-	return &result.Data.Order, nil
+// CreateSellLimit places a sell order with PlaceOrder.
+func (client *Client) CreateSellLimit(ctx context.Context, amount Decimal, market Market, price Decimal) (*Order, error) {
+	return client.PlaceOrder(ctx, OrderRequest{Market: market, Side: SellLimit, Amount: amount, Price: price})
 }
 
-func (client *Client) CreateBuyLimit(ctx context.Context, amount float64, market Market, price float64) (*Order, error) {
-	result := new(CreateOrderResult)
-
-	body := map[string]interface{}{
-		"amount":    strconv.FormatFloat(amount, 'f', CurrencyDecimalPlaces[market.MarketCurrency()], 64),
-		"market_id": market,
-		"price":     strconv.FormatFloat(price, 'f', CurrencyDecimalPlaces[market.BaseCurrency()], 64),
-	}
-
-	bodyBytes, err := json.Marshal(body)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create buy order for "+market.String())
-	}
-
-	req, err := http.NewRequestWithContext(ctx,
-		"POST",
-		client.Config.Endpoint+"/v1/user/buy_limit",
-		bytes.NewReader(bodyBytes))
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create buy order")
-	}
-
-	err = client.doRequest(req, result, nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create buy order for "+market.String())
-	}
-
-	return &result.Data.Order, nil
+// CreateBuyLimit places a buy order with PlaceOrder.
+func (client *Client) CreateBuyLimit(ctx context.Context, amount Decimal, market Market, price Decimal) (*Order, error) {
+	return client.PlaceOrder(ctx, OrderRequest{Market: market, Side: BuyLimit, Amount: amount, Price: price})
 }
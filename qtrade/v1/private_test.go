@@ -2,10 +2,13 @@ package qtrade
 
 import (
 	"context"
+	"io/ioutil"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/jarcoal/httpmock"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -88,15 +91,15 @@ func TestClient_GetBalances(t *testing.T) {
 	want := []Balance{
 		{
 			Currency: DOGE,
-			Balance:  "100000000",
+			Balance:  MustParseDecimal("100000000"),
 		},
 		{
 			Currency: LTC,
-			Balance:  "99992435.78253015",
+			Balance:  MustParseDecimal("99992435.78253015"),
 		},
 		{
 			Currency: BTC,
-			Balance:  "99927153.76074182",
+			Balance:  MustParseDecimal("99927153.76074182"),
 		},
 	}
 
@@ -122,72 +125,72 @@ func TestClient_GetUserMarket(t *testing.T) {
 	t4, _ := time.Parse(time.RFC3339, "2018-04-06T17:59:27.531716Z")
 
 	want := &UserMarketData{
-		BaseBalance: 99927153.76074182,
+		BaseBalance: MustParseDecimal("99927153.76074182"),
 		ClosedOrders: []Order{
 			{
-				BaseAmount:            0.09102782,
+				BaseAmount:            MustParseDecimal("0.09102782"),
 				CreatedAt:             t1,
 				ID:                    13252,
-				MarketAmount:          4.99896025,
-				MarketAmountRemaining: 0,
+				MarketAmount:          MustParseDecimal("4.99896025"),
+				MarketAmountRemaining: MustParseDecimal("0"),
 				Market:                LTC_BTC,
 				Open:                  false,
 				OrderType:             "buy_limit",
-				Price:                 9.90682437,
+				Price:                 MustParseDecimal("9.90682437"),
 				Trades: []PrivateTrade{
 					{
-						BaseAmount:   49.37394186,
-						BaseFee:      0.12343485,
+						BaseAmount:   MustParseDecimal("49.37394186"),
+						BaseFee:      MustParseDecimal("0.12343485"),
 						CreatedAt:    t1,
 						ID:           10289,
-						MarketAmount: 4.99298105,
-						Price:        9.88866999,
+						MarketAmount: MustParseDecimal("4.99298105"),
+						Price:        MustParseDecimal("9.88866999"),
 						Taker:        true,
 					},
 					{
-						BaseAmount:   0.05907856,
-						BaseFee:      0.00014769,
+						BaseAmount:   MustParseDecimal("0.05907856"),
+						BaseFee:      MustParseDecimal("0.00014769"),
 						CreatedAt:    t1,
 						ID:           10288,
-						MarketAmount: 0.0059792,
-						Price:        9.88068047,
+						MarketAmount: MustParseDecimal("0.0059792"),
+						Price:        MustParseDecimal("9.88068047"),
 						Taker:        true,
 					},
 				},
 			},
 		},
-		MarketBalance: 99992435.78253015,
+		MarketBalance: MustParseDecimal("99992435.78253015"),
 		OpenOrders: []Order{
 			{
-				BaseAmount:            49.45063516,
+				BaseAmount:            MustParseDecimal("49.45063516"),
 				CreatedAt:             t2,
 				ID:                    13249,
-				MarketAmount:          5.0007505,
-				MarketAmountRemaining: 5.0007505,
+				MarketAmount:          MustParseDecimal("5.0007505"),
+				MarketAmountRemaining: MustParseDecimal("5.0007505"),
 				Market:                LTC_BTC,
 				Open:                  true,
 				OrderType:             "buy_limit",
-				Price:                 9.86398279,
+				Price:                 MustParseDecimal("9.86398279"),
 				Trades:                nil,
 			},
 			{
-				BaseAmount:            0,
+				BaseAmount:            MustParseDecimal("0"),
 				CreatedAt:             t3,
 				ID:                    13192,
-				MarketAmount:          5.00245975,
-				MarketAmountRemaining: 0.0173805,
+				MarketAmount:          MustParseDecimal("5.00245975"),
+				MarketAmountRemaining: MustParseDecimal("0.0173805"),
 				Market:                LTC_BTC,
 				Open:                  true,
 				OrderType:             "sell_limit",
-				Price:                 9.90428849,
+				Price:                 MustParseDecimal("9.90428849"),
 				Trades: []PrivateTrade{
 					{
-						BaseAmount:   49.37366303,
-						BaseFee:      0.12343415,
+						BaseAmount:   MustParseDecimal("49.37366303"),
+						BaseFee:      MustParseDecimal("0.12343415"),
 						CreatedAt:    t4,
 						ID:           10241,
-						MarketAmount: 4.98507925,
-						Price:        9.90428849,
+						MarketAmount: MustParseDecimal("4.98507925"),
+						Price:        MustParseDecimal("9.90428849"),
 						Taker:        false,
 					},
 				},
@@ -216,46 +219,46 @@ func TestClient_GetOrders(t *testing.T) {
 
 	want := []Order{
 		{
-			BaseAmount:            0.09102782,
+			BaseAmount:            MustParseDecimal("0.09102782"),
 			CreatedAt:             t1,
 			ID:                    13252,
-			MarketAmount:          4.99896025,
-			MarketAmountRemaining: 0,
+			MarketAmount:          MustParseDecimal("4.99896025"),
+			MarketAmountRemaining: MustParseDecimal("0"),
 			Market:                LTC_BTC,
 			Open:                  false,
 			OrderType:             "buy_limit",
-			Price:                 9.90682437,
+			Price:                 MustParseDecimal("9.90682437"),
 			Trades: []PrivateTrade{
 				{
-					BaseAmount:   49.37394186,
-					BaseFee:      0.12343485,
+					BaseAmount:   MustParseDecimal("49.37394186"),
+					BaseFee:      MustParseDecimal("0.12343485"),
 					CreatedAt:    t1,
 					ID:           10289,
-					MarketAmount: 4.99298105,
-					Price:        9.88866999,
+					MarketAmount: MustParseDecimal("4.99298105"),
+					Price:        MustParseDecimal("9.88866999"),
 					Taker:        true,
 				},
 				{
-					BaseAmount:   0.05907856,
-					BaseFee:      0.00014769,
+					BaseAmount:   MustParseDecimal("0.05907856"),
+					BaseFee:      MustParseDecimal("0.00014769"),
 					CreatedAt:    t1,
 					ID:           10288,
-					MarketAmount: 0.0059792,
-					Price:        9.88068047,
+					MarketAmount: MustParseDecimal("0.0059792"),
+					Price:        MustParseDecimal("9.88068047"),
 					Taker:        true,
 				},
 			},
 		},
 		{
-			BaseAmount:            49.33046306,
+			BaseAmount:            MustParseDecimal("49.33046306"),
 			CreatedAt:             t2,
 			ID:                    13099,
-			MarketAmount:          4.9950993,
-			MarketAmountRemaining: 4.9950993,
+			MarketAmount:          MustParseDecimal("4.9950993"),
+			MarketAmountRemaining: MustParseDecimal("4.9950993"),
 			Market:                LTC_BTC,
 			Open:                  true,
 			OrderType:             "buy_limit",
-			Price:                 9.85114439,
+			Price:                 MustParseDecimal("9.85114439"),
 			Trades:                []PrivateTrade(nil),
 		},
 	}
@@ -279,15 +282,15 @@ func TestClient_GetOrder(t *testing.T) {
 	t1, _ := time.Parse(time.RFC3339, "2018-11-08T00:15:57.258122Z")
 
 	want := &Order{
-		BaseAmount:            0,
+		BaseAmount:            MustParseDecimal("0"),
 		CreatedAt:             t1,
 		ID:                    8806681,
-		MarketAmount:          500,
-		MarketAmountRemaining: 0,
+		MarketAmount:          MustParseDecimal("500"),
+		MarketAmountRemaining: MustParseDecimal("0"),
 		Market:                DOGE_BTC,
 		Open:                  false,
 		OrderType:             "sell_limit",
-		Price:                 0.00000033,
+		Price:                 MustParseDecimal("0.00000033"),
 		Trades:                nil,
 		CloseReason:           "canceled",
 	}
@@ -313,38 +316,38 @@ func TestClient_GetTrades(t *testing.T) {
 
 	want := []PrivateTrade{
 		{
-			BaseAmount:   0.00022751,
-			BaseFee:      0,
+			BaseAmount:   MustParseDecimal("0.00022751"),
+			BaseFee:      MustParseDecimal("0"),
 			CreatedAt:    t1,
 			ID:           63286,
 			OrderID:      8141515,
 			Market:       DOGE_BTC,
-			MarketAmount: 733.93113296,
-			Price:        0.00000031,
+			MarketAmount: MustParseDecimal("733.93113296"),
+			Price:        MustParseDecimal("0.00000031"),
 			Taker:        false,
 			Side:         "sell",
 		},
 		{
-			BaseAmount:   0.000434,
-			BaseFee:      0.00000217,
+			BaseAmount:   MustParseDecimal("0.000434"),
+			BaseFee:      MustParseDecimal("0.00000217"),
 			CreatedAt:    t1,
 			ID:           63287,
 			OrderID:      8141515,
 			Market:       DOGE_BTC,
-			MarketAmount: 1400,
-			Price:        0.00000031,
+			MarketAmount: MustParseDecimal("1400"),
+			Price:        MustParseDecimal("0.00000031"),
 			Taker:        true,
 			Side:         "sell",
 		},
 		{
-			BaseAmount:   0.000135,
-			BaseFee:      0,
+			BaseAmount:   MustParseDecimal("0.000135"),
+			BaseFee:      MustParseDecimal("0"),
 			CreatedAt:    t2,
 			ID:           64129,
 			OrderID:      8209249,
 			Market:       DOGE_BTC,
-			MarketAmount: 500,
-			Price:        0.00000027,
+			MarketAmount: MustParseDecimal("500"),
+			Price:        MustParseDecimal("0.00000027"),
 			Taker:        false,
 			Side:         "buy",
 		},
@@ -378,7 +381,12 @@ func TestClient_Withdraw(t *testing.T) {
 
 	// Exact URL match
 	httpmock.RegisterResponder("POST", "http://localhost/v1/user/withdraw",
-		httpmock.NewStringResponder(200, withdrawTestData))
+		func(req *http.Request) (*http.Response, error) {
+			body, _ := ioutil.ReadAll(req.Body)
+			assert.JSONEq(t, `{"address":"abcd","amount":"20.00000000","currency":"BTC"}`, string(body))
+
+			return httpmock.NewStringResponse(200, withdrawTestData), nil
+		})
 
 	want := &WithdrawData{
 		Code:   "initiated",
@@ -386,7 +394,7 @@ func TestClient_Withdraw(t *testing.T) {
 		Result: "Withdraw initiated. Please allow 3-5 minutes for our system to process.",
 	}
 
-	got, err := testClient.Withdraw(context.Background(), "abcd", 20, BTC)
+	got, err := testClient.Withdraw(context.Background(), "abcd", DecimalFromInt(20), BTC)
 	if assert.NoError(t, err) {
 		assert.Equal(t, want, got)
 	}
@@ -394,6 +402,50 @@ func TestClient_Withdraw(t *testing.T) {
 	assert.Equal(t, 1, httpmock.GetCallCountInfo()["POST http://localhost/v1/user/withdraw"])
 }
 
+func TestClient_Withdraw_Invalid(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "http://localhost/v1/user/withdraw",
+		httpmock.NewStringResponder(200, withdrawTestData))
+
+	testCases := []struct {
+		name     string
+		amount   Decimal
+		currency Currency
+		wantErr  string
+	}{
+		{
+			name:     "unknown precision",
+			amount:   MustParseDecimal("0.6"),
+			currency: "XYZ",
+			wantErr:  "unknown precision of XYZ: invalid withdrawal",
+		},
+		{
+			name:     "too many places",
+			amount:   MustParseDecimal("0.123456789"),
+			currency: BTC,
+			wantErr:  "amount 0.123456789 has more than the 8 decimal places of BTC: invalid withdrawal",
+		},
+		{
+			name:     "not positive",
+			amount:   MustParseDecimal("-1"),
+			currency: BTC,
+			wantErr:  "amount -1 of BTC is not positive: invalid withdrawal",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := testClient.Withdraw(context.Background(), "abcd", tc.amount, tc.currency)
+			assert.EqualError(t, err, tc.wantErr)
+			assert.True(t, errors.Is(err, ErrInvalidWithdrawal))
+		})
+	}
+
+	assert.Equal(t, 0, httpmock.GetTotalCallCount())
+}
+
 func TestClient_GetWithdrawDetails(t *testing.T) {
 	httpmock.Activate()
 	defer httpmock.DeactivateAndReset()
@@ -406,7 +458,7 @@ func TestClient_GetWithdrawDetails(t *testing.T) {
 
 	want := &WithdrawDetails{
 		Address:         "mw67t7AE88SBSRWYw1is3JaFbtXVygwpmB",
-		Amount:          "1",
+		Amount:          MustParseDecimal("1"),
 		CancelRequested: false,
 		CreatedAt:       wantTime,
 		Currency:        "LTC",
@@ -438,7 +490,7 @@ func TestClient_GetWithdrawHistory(t *testing.T) {
 	want := []WithdrawDetails{
 		{
 			Address:         "mw67t7AE88SBSRWYw1is3JaFbtXVygwpmB",
-			Amount:          "1",
+			Amount:          MustParseDecimal("1"),
 			CancelRequested: false,
 			CreatedAt:       wantTime,
 			Currency:        "LTC",
@@ -471,7 +523,7 @@ func TestClient_GetDeposit(t *testing.T) {
 	want := []DepositDetails{
 		{
 			Address:     "1CK6KHY6MHgYvmRQ4PAafKYDrg1ejbH1cE",
-			Amount:      "1",
+			Amount:      MustParseDecimal("1"),
 			CreatedAt:   wantTime,
 			Currency:    "BTC",
 			ID:          "ab5e1720944065ad64917929082191270896edc1b17d18e921aa5b1b26e18ab4",
@@ -502,7 +554,7 @@ func TestClient_GetDepositHistory(t *testing.T) {
 	want := []DepositDetails{
 		{
 			Address:   "1Kv3CKUigVPsxGCkkaoyLKrZHZ7WLq8jNK",
-			Amount:    "0.25",
+			Amount:    MustParseDecimal("0.25"),
 			CreatedAt: wantTime,
 			Currency:  "BTC",
 			ID:        "1:855e291e4acd61c21fcbf1bc31aa2578fa8eb3b388d9e979077567a71b58f088",
@@ -558,7 +610,7 @@ func TestClient_GetTransfers(t *testing.T) {
 
 	want := []Transfer{
 		{
-			Amount:     0.5,
+			Amount:     MustParseDecimal("0.5"),
 			CreatedAt:  wantTime,
 			Currency:   BTC,
 			ID:         9,
@@ -583,6 +635,8 @@ func TestClient_CreateSellLimit(t *testing.T) {
 	httpmock.Activate()
 	defer httpmock.DeactivateAndReset()
 
+	registerTradableMarket()
+
 	// Exact URL match
 	httpmock.RegisterResponder("POST", "http://localhost/v1/user/sell_limit",
 		httpmock.NewStringResponder(200, sellLimitData))
@@ -593,37 +647,37 @@ func TestClient_CreateSellLimit(t *testing.T) {
 	want := &Order{
 		CreatedAt:             createdTime,
 		ID:                    13253,
-		MarketAmount:          1,
-		MarketAmountRemaining: 0,
+		MarketAmount:          MustParseDecimal("1"),
+		MarketAmountRemaining: MustParseDecimal("0"),
 		Market:                LTC_BTC,
 		Open:                  false,
 		OrderType:             SellLimit,
-		Price:                 0.01,
+		Price:                 MustParseDecimal("0.01"),
 		Trades: []PrivateTrade{
 			{
-				BaseAmount:   0.27834267,
-				BaseFee:      0.00069585,
+				BaseAmount:   MustParseDecimal("0.27834267"),
+				BaseFee:      MustParseDecimal("0.00069585"),
 				CreatedAt:    tradeTime,
 				ID:           0,
 				OrderID:      0,
-				MarketAmount: 0.02820645,
-				Price:        9.86805058,
+				MarketAmount: MustParseDecimal("0.02820645"),
+				Price:        MustParseDecimal("9.86805058"),
 				Taker:        true,
 			},
 			{
-				BaseAmount:   9.58970687,
-				BaseFee:      0.02397426,
+				BaseAmount:   MustParseDecimal("9.58970687"),
+				BaseFee:      MustParseDecimal("0.02397426"),
 				CreatedAt:    tradeTime,
 				ID:           0,
 				OrderID:      0,
-				MarketAmount: 0.97179355,
-				Price:        9.86804952,
+				MarketAmount: MustParseDecimal("0.97179355"),
+				Price:        MustParseDecimal("9.86804952"),
 				Taker:        true,
 			},
 		},
 	}
 
-	got, err := testClient.CreateSellLimit(context.Background(), 1, LTC_BTC, 0.01)
+	got, err := testClient.CreateSellLimit(context.Background(), DecimalFromInt(1), LTC_BTC, MustParseDecimal("0.01"))
 	if assert.NoError(t, err) {
 		assert.Equal(t, want, got)
 	}
@@ -635,6 +689,8 @@ func TestClient_CreateBuyLimit(t *testing.T) {
 	httpmock.Activate()
 	defer httpmock.DeactivateAndReset()
 
+	registerTradableMarket()
+
 	// Exact URL match
 	httpmock.RegisterResponder("POST", "http://localhost/v1/user/buy_limit",
 		httpmock.NewStringResponder(200, buyLimitData))
@@ -642,19 +698,19 @@ func TestClient_CreateBuyLimit(t *testing.T) {
 	createdTime, _ := time.Parse(time.RFC3339Nano, "2018-04-06T20:47:11.966139Z")
 
 	want := &Order{
-		BaseAmount:            1.0025,
+		BaseAmount:            MustParseDecimal("1.0025"),
 		CreatedAt:             createdTime,
 		ID:                    13254,
-		MarketAmount:          10,
-		MarketAmountRemaining: 10,
+		MarketAmount:          MustParseDecimal("10"),
+		MarketAmountRemaining: MustParseDecimal("10"),
 		Market:                LTC_BTC,
 		Open:                  true,
 		OrderType:             BuyLimit,
-		Price:                 0.1,
+		Price:                 MustParseDecimal("0.1"),
 		Trades:                []PrivateTrade{},
 	}
 
-	got, err := testClient.CreateBuyLimit(context.Background(), 10, LTC_BTC, 0.1)
+	got, err := testClient.CreateBuyLimit(context.Background(), DecimalFromInt(10), LTC_BTC, MustParseDecimal("0.1"))
 	if assert.NoError(t, err) {
 		assert.Equal(t, want, got)
 	}
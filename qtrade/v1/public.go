@@ -3,8 +3,6 @@ package qtrade
 import (
 	"context"
 	"fmt"
-	"net/http"
-	"strconv"
 
 	"github.com/pkg/errors"
 )
@@ -12,14 +10,7 @@ import (
 func (client *Client) GetCommon(ctx context.Context) (*CommonData, error) {
 	result := new(GetCommonResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET",
-		client.Config.Endpoint+"/v1/common",
-		nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get common data")
-	}
-
-	err = client.doRequest(req, result, nil)
+	err := client.doRequest(ctx, apiRequest{method: "GET", path: "/v1/common"}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get common data")
 	}
@@ -30,16 +21,9 @@ func (client *Client) GetCommon(ctx context.Context) (*CommonData, error) {
 func (client *Client) GetTicker(ctx context.Context, market Market) (*Ticker, error) {
 	result := new(GetTickerResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET",
-		client.Config.Endpoint+"/v1/ticker/"+market.String(),
-		nil)
+	err := client.doRequest(ctx, apiRequest{method: "GET", path: "/v1/ticker/" + client.marketName(market)}, result)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get ticker for "+market.String())
-	}
-
-	err = client.doRequest(req, result, nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get ticker for "+market.String())
+		return nil, errors.Wrap(err, "failed to get ticker for "+client.marketName(market))
 	}
 
 	return &result.Data, nil
@@ -48,14 +32,7 @@ func (client *Client) GetTicker(ctx context.Context, market Market) (*Ticker, er
 func (client *Client) GetTickers(ctx context.Context) ([]Ticker, error) {
 	result := new(GetTickersResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET",
-		client.Config.Endpoint+"/v1/tickers",
-		nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get tickers")
-	}
-
-	err = client.doRequest(req, result, nil)
+	err := client.doRequest(ctx, apiRequest{method: "GET", path: "/v1/tickers"}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get tickers")
 	}
@@ -66,14 +43,7 @@ func (client *Client) GetTickers(ctx context.Context) ([]Ticker, error) {
 func (client *Client) GetCurrency(ctx context.Context, currency Currency) (*CurrencyData, error) {
 	result := new(GetCurrencyResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET",
-		client.Config.Endpoint+"/v1/currency/"+string(currency),
-		nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get currency "+string(currency))
-	}
-
-	err = client.doRequest(req, result, nil)
+	err := client.doRequest(ctx, apiRequest{method: "GET", path: "/v1/currency/" + string(currency)}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get currency "+string(currency))
 	}
@@ -84,14 +54,7 @@ func (client *Client) GetCurrency(ctx context.Context, currency Currency) (*Curr
 func (client *Client) GetCurrencies(ctx context.Context) ([]CurrencyData, error) {
 	result := new(GetCurrenciesResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET",
-		client.Config.Endpoint+"/v1/currencies",
-		nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get currencies")
-	}
-
-	err = client.doRequest(req, result, nil)
+	err := client.doRequest(ctx, apiRequest{method: "GET", path: "/v1/currencies"}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get currencies")
 	}
@@ -102,16 +65,9 @@ func (client *Client) GetCurrencies(ctx context.Context) ([]CurrencyData, error)
 func (client *Client) GetMarket(ctx context.Context, market Market) (*GetMarketData, error) {
 	result := new(GetMarketResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET",
-		client.Config.Endpoint+"/v1/market/"+market.String(),
-		nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get market "+market.String())
-	}
-
-	err = client.doRequest(req, result, nil)
+	err := client.doRequest(ctx, apiRequest{method: "GET", path: "/v1/market/" + client.marketName(market)}, result)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get market "+market.String())
+		return nil, errors.Wrap(err, "failed to get market "+client.marketName(market))
 	}
 
 	return &result.Data, nil
@@ -120,14 +76,7 @@ func (client *Client) GetMarket(ctx context.Context, market Market) (*GetMarketD
 func (client *Client) GetMarkets(ctx context.Context) ([]MarketData, error) {
 	result := new(GetMarketsResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET",
-		client.Config.Endpoint+"/v1/markets",
-		nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get markets")
-	}
-
-	err = client.doRequest(req, result, nil)
+	err := client.doRequest(ctx, apiRequest{method: "GET", path: "/v1/markets"}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get markets")
 	}
@@ -138,16 +87,12 @@ func (client *Client) GetMarkets(ctx context.Context) ([]MarketData, error) {
 func (client *Client) GetMarketTrades(ctx context.Context, market Market) ([]PublicTrade, error) {
 	result := new(GetMarketTradesResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET",
-		fmt.Sprintf("%s/v1/market/%s/trades", client.Config.Endpoint, market.String()),
-		nil)
+	err := client.doRequest(ctx, apiRequest{
+		method: "GET",
+		path:   fmt.Sprintf("/v1/market/%s/trades", client.marketName(market)),
+	}, result)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get market trades for "+market.String())
-	}
-
-	err = client.doRequest(req, result, nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get market trades for "+market.String())
+		return nil, errors.Wrap(err, "failed to get market trades for "+client.marketName(market))
 	}
 
 	return result.Data.Trades, nil
@@ -156,70 +101,34 @@ func (client *Client) GetMarketTrades(ctx context.Context, market Market) ([]Pub
 func (client *Client) GetOrderbook(ctx context.Context, market Market) (*Orderbook, error) {
 	result := new(GetOrderbookResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET",
-		client.Config.Endpoint+"/v1/orderbook/"+market.String(),
-		nil)
+	err := client.doRequest(ctx, apiRequest{method: "GET", path: "/v1/orderbook/" + client.marketName(market)}, result)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get orderbook for "+market.String())
+		return nil, errors.Wrap(err, "failed to get orderbook for "+client.marketName(market))
 	}
 
-	err = client.doRequest(req, result, nil)
+	bids, err := parseOrderbookLevels(result.Data.Buy)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get orderbook for "+market.String())
+		return nil, errors.Wrap(err, "failed to get orderbook for "+client.marketName(market))
 	}
 
-	floatBuy, err := stringMapToFloatMap(result.Data.Buy)
+	asks, err := parseOrderbookLevels(result.Data.Sell)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get orderbook for "+market.String())
-	}
-
-	floatSell, err := stringMapToFloatMap(result.Data.Sell)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get orderbook for "+market.String())
-	}
-
-	orderbook := &Orderbook{
-		Buy:        floatBuy,
-		LastChange: result.Data.LastChange,
-		Sell:       floatSell,
+		return nil, errors.Wrap(err, "failed to get orderbook for "+client.marketName(market))
 	}
 
-	return orderbook, nil
-}
-
-func stringMapToFloatMap(input map[string]string) (map[float64]float64, error) {
-	output := make(map[float64]float64, len(input))
-
-	for k, v := range input {
-		floatK, err := strconv.ParseFloat(k, 64)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to parse string map to float map")
-		}
-
-		floatV, err := strconv.ParseFloat(v, 64)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to parse string map to float map")
-		}
-
-		output[floatK] = floatV
-	}
-
-	return output, nil
+	return NewOrderbook(bids, asks, result.Data.LastChange), nil
 }
 
 func (client *Client) GetOHLCV(ctx context.Context, market Market, interval Interval, params map[string]string) ([]OHLCVSlice, error) {
 	result := new(GetOHLCVResult)
 
-	req, err := http.NewRequestWithContext(ctx, "GET",
-		fmt.Sprintf("%s/v1/market/%s/ohlcv/%s", client.Config.Endpoint, market.String(), interval),
-		nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get OHLCV for market "+market.String())
-	}
-
-	err = client.doRequest(req, result, params)
+	err := client.doRequest(ctx, apiRequest{
+		method: "GET",
+		path:   fmt.Sprintf("/v1/market/%s/ohlcv/%s", client.marketName(market), interval),
+		params: params,
+	}, result)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get OHLCV for market "+market.String())
+		return nil, errors.Wrap(err, "failed to get OHLCV for market "+client.marketName(market))
 	}
 
 	return result.Data.Slices, nil
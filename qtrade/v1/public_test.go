@@ -38,12 +38,12 @@ func TestClient_GetCommon(t *testing.T) {
 				Config: CurrencyConfig{
 					AddressVersion:                50,
 					DefaultSigner:                 23,
-					Price:                         0.002,
+					Price:                         MustParseDecimal("0.002"),
 					RequiredConfirmations:         6,
 					RequiredGenerateConfirmations: 120,
 					SatoshiPerByte:                100,
 					WifVersion:                    178,
-					WithdrawFee:                   0.001,
+					WithdrawFee:                   MustParseDecimal("0.001"),
 					ExplorerAddressURL:            "",
 					ExplorerTransactionURL:        "",
 					P2ShAddressVersion:            0,
@@ -67,12 +67,12 @@ func TestClient_GetCommon(t *testing.T) {
 				Config: CurrencyConfig{
 					AddressVersion:                0,
 					DefaultSigner:                 6,
-					Price:                         8595.59,
+					Price:                         MustParseDecimal("8595.59"),
 					RequiredConfirmations:         2,
 					RequiredGenerateConfirmations: 100,
 					SatoshiPerByte:                15,
 					WifVersion:                    0,
-					WithdrawFee:                   0.0005,
+					WithdrawFee:                   MustParseDecimal("0.0005"),
 					ExplorerAddressURL:            "https://live.blockcypher.com/btc/address/",
 					ExplorerTransactionURL:        "https://live.blockcypher.com/btc/tx/",
 					P2ShAddressVersion:            5,
@@ -96,12 +96,12 @@ func TestClient_GetCommon(t *testing.T) {
 				Config: CurrencyConfig{
 					AddressVersion:                0,
 					DefaultSigner:                 54,
-					Price:                         0.12891653720125376,
+					Price:                         MustParseDecimal("0.12891653720125376"),
 					RequiredConfirmations:         35,
 					RequiredGenerateConfirmations: 0,
 					SatoshiPerByte:                0,
 					WifVersion:                    0,
-					WithdrawFee:                   0.25,
+					WithdrawFee:                   MustParseDecimal("0.25"),
 					ExplorerAddressURL:            "https://bismuth.online/search?quicksearch=",
 					ExplorerTransactionURL:        "https://bismuth.online/search?quicksearch=",
 					P2ShAddressVersion:            0,
@@ -127,7 +127,7 @@ func TestClient_GetCommon(t *testing.T) {
 				CanTrade:       false,
 				CanView:        false,
 				ID:             MMO_BTC,
-				MakerFee:       0.0025,
+				MakerFee:       MustParseDecimal("0.0025"),
 				MarketCurrency: MMO,
 				Metadata: MarketMetadata{
 					DelistingDate: "12/13/2018",
@@ -139,7 +139,7 @@ func TestClient_GetCommon(t *testing.T) {
 					},
 					Labels: nil,
 				},
-				TakerFee: 0.0025,
+				TakerFee: MustParseDecimal("0.0025"),
 			},
 			{
 				BaseCurrency:   "BTC",
@@ -147,44 +147,44 @@ func TestClient_GetCommon(t *testing.T) {
 				CanTrade:       true,
 				CanView:        true,
 				ID:             BIS_BTC,
-				MakerFee:       0,
+				MakerFee:       MustParseDecimal("0"),
 				MarketCurrency: "BIS",
 				Metadata: MarketMetadata{
 					DelistingDate: "",
 					MarketNotices: nil,
 					Labels:        []interface{}{},
 				},
-				TakerFee: 0.005,
+				TakerFee: MustParseDecimal("0.005"),
 			},
 		},
 		Tickers: []Ticker{
 			{
-				Ask:             0,
-				Bid:             0,
-				DayAvgPrice:     0,
-				DayChange:       0,
-				DayHigh:         0,
-				DayLow:          0,
-				DayOpen:         0,
-				DayVolumeBase:   0,
-				DayVolumeMarket: 0,
+				Ask:             MustParseDecimal("0"),
+				Bid:             MustParseDecimal("0"),
+				DayAvgPrice:     MustParseDecimal("0"),
+				DayChange:       MustParseDecimal("0"),
+				DayHigh:         MustParseDecimal("0"),
+				DayLow:          MustParseDecimal("0"),
+				DayOpen:         MustParseDecimal("0"),
+				DayVolumeBase:   MustParseDecimal("0"),
+				DayVolumeMarket: MustParseDecimal("0"),
 				Market:          MMO_BTC,
 				IDHr:            "MMO_BTC",
-				Last:            0.00000076,
+				Last:            MustParseDecimal("0.00000076"),
 			},
 			{
-				Ask:             0.000014,
-				Bid:             0.00001324,
-				DayAvgPrice:     0.0000147000191353,
-				DayChange:       -0.023086269744836,
-				DayHigh:         0.00001641,
-				DayLow:          0.00001292,
-				DayOpen:         0.00001646,
-				DayVolumeBase:   0.36885974,
-				DayVolumeMarket: 25092.46665642,
+				Ask:             MustParseDecimal("0.000014"),
+				Bid:             MustParseDecimal("0.00001324"),
+				DayAvgPrice:     MustParseDecimal("0.0000147000191353"),
+				DayChange:       MustParseDecimal("-0.023086269744836"),
+				DayHigh:         MustParseDecimal("0.00001641"),
+				DayLow:          MustParseDecimal("0.00001292"),
+				DayOpen:         MustParseDecimal("0.00001646"),
+				DayVolumeBase:   MustParseDecimal("0.36885974"),
+				DayVolumeMarket: MustParseDecimal("25092.46665642"),
 				Market:          BIS_BTC,
 				IDHr:            "BIS_BTC",
-				Last:            0.00001608,
+				Last:            MustParseDecimal("0.00001608"),
 			},
 		},
 	}
@@ -206,18 +206,18 @@ func TestClient_GetTicker(t *testing.T) {
 		httpmock.NewStringResponder(200, tickerTestData))
 
 	want := &Ticker{
-		Ask:             0.02249,
-		Bid:             0.0191,
-		DayAvgPrice:     0.0197095311101552,
-		DayChange:       0.0380429141071119,
-		DayHigh:         0.02249,
-		DayLow:          0.0184,
-		DayOpen:         0.01840001,
-		DayVolumeBase:   0.42644484,
-		DayVolumeMarket: 21.63647819,
+		Ask:             MustParseDecimal("0.02249"),
+		Bid:             MustParseDecimal("0.0191"),
+		DayAvgPrice:     MustParseDecimal("0.0197095311101552"),
+		DayChange:       MustParseDecimal("0.0380429141071119"),
+		DayHigh:         MustParseDecimal("0.02249"),
+		DayLow:          MustParseDecimal("0.0184"),
+		DayOpen:         MustParseDecimal("0.01840001"),
+		DayVolumeBase:   MustParseDecimal("0.42644484"),
+		DayVolumeMarket: MustParseDecimal("21.63647819"),
 		Market:          VEO_BTC,
 		IDHr:            VEO_BTC.String(),
-		Last:            0.0191,
+		Last:            MustParseDecimal("0.0191"),
 	}
 
 	got, err := testClient.GetTicker(context.Background(), VEO_BTC)
@@ -238,32 +238,32 @@ func TestClient_GetTickers(t *testing.T) {
 
 	want := []Ticker{
 		{
-			Ask:             0.0034,
-			Bid:             0.0011,
-			DayAvgPrice:     0,
-			DayChange:       0,
-			DayHigh:         0,
-			DayLow:          0,
-			DayOpen:         0,
-			DayVolumeBase:   0,
-			DayVolumeMarket: 0,
+			Ask:             MustParseDecimal("0.0034"),
+			Bid:             MustParseDecimal("0.0011"),
+			DayAvgPrice:     MustParseDecimal("0"),
+			DayChange:       MustParseDecimal("0"),
+			DayHigh:         MustParseDecimal("0"),
+			DayLow:          MustParseDecimal("0"),
+			DayOpen:         MustParseDecimal("0"),
+			DayVolumeBase:   MustParseDecimal("0"),
+			DayVolumeMarket: MustParseDecimal("0"),
 			Market:          GRIN_BTC,
 			IDHr:            "GRIN_BTC",
-			Last:            0.0033,
+			Last:            MustParseDecimal("0.0033"),
 		},
 		{
-			Ask:             0.000099,
-			Bid:             0.0000795,
-			DayAvgPrice:     0.0000795337894515,
-			DayChange:       -0.2205882352941176,
-			DayHigh:         0.00008,
-			DayLow:          0.0000795,
-			DayOpen:         0.000102,
-			DayVolumeBase:   0.07353291,
-			DayVolumeMarket: 924.549308,
+			Ask:             MustParseDecimal("0.000099"),
+			Bid:             MustParseDecimal("0.0000795"),
+			DayAvgPrice:     MustParseDecimal("0.0000795337894515"),
+			DayChange:       MustParseDecimal("-0.2205882352941176"),
+			DayHigh:         MustParseDecimal("0.00008"),
+			DayLow:          MustParseDecimal("0.0000795"),
+			DayOpen:         MustParseDecimal("0.000102"),
+			DayVolumeBase:   MustParseDecimal("0.07353291"),
+			DayVolumeMarket: MustParseDecimal("924.549308"),
 			Market:          SNOW_BTC,
 			IDHr:            "SNOW_BTC",
-			Last:            0.0000795,
+			Last:            MustParseDecimal("0.0000795"),
 		},
 	}
 
@@ -289,11 +289,11 @@ func TestClient_GetCurrency(t *testing.T) {
 		Config: CurrencyConfig{
 			AddressVersion:                0,
 			DefaultSigner:                 6,
-			Price:                         9159.72,
+			Price:                         MustParseDecimal("9159.72"),
 			RequiredConfirmations:         2,
 			RequiredGenerateConfirmations: 100,
 			SatoshiPerByte:                15,
-			WithdrawFee:                   0.0005,
+			WithdrawFee:                   MustParseDecimal("0.0005"),
 			ExplorerAddressURL:            "https://live.blockcypher.com/btc/address/",
 			ExplorerTransactionURL:        "https://live.blockcypher.com/btc/tx/",
 			P2ShAddressVersion:            5,
@@ -330,11 +330,11 @@ func TestClient_GetCurrencies(t *testing.T) {
 			Config: CurrencyConfig{
 				AddressVersion:                0,
 				DefaultSigner:                 6,
-				Price:                         9159.72,
+				Price:                         MustParseDecimal("9159.72"),
 				RequiredConfirmations:         2,
 				RequiredGenerateConfirmations: 100,
 				SatoshiPerByte:                15,
-				WithdrawFee:                   0.0005,
+				WithdrawFee:                   MustParseDecimal("0.0005"),
 				ExplorerAddressURL:            "https://live.blockcypher.com/btc/address/",
 				ExplorerTransactionURL:        "https://live.blockcypher.com/btc/tx/",
 				P2ShAddressVersion:            5,
@@ -352,9 +352,9 @@ func TestClient_GetCurrencies(t *testing.T) {
 			Code:        BIS,
 			Config: CurrencyConfig{
 				DefaultSigner:          54,
-				Price:                  0.11314929085578249,
+				Price:                  MustParseDecimal("0.11314929085578249"),
 				RequiredConfirmations:  35,
-				WithdrawFee:            0.25,
+				WithdrawFee:            MustParseDecimal("0.25"),
 				ExplorerAddressURL:     "https://bismuth.online/search?quicksearch=",
 				ExplorerTransactionURL: "https://bismuth.online/search?quicksearch=",
 				EnableAddressData:      true,
@@ -397,23 +397,23 @@ func TestClient_GetMarket(t *testing.T) {
 			CanTrade:       true,
 			CanView:        false,
 			ID:             VEO_BTC,
-			MakerFee:       0.005,
+			MakerFee:       MustParseDecimal("0.005"),
 			MarketCurrency: VEO,
 			Metadata:       MarketMetadata{},
-			TakerFee:       0.005,
+			TakerFee:       MustParseDecimal("0.005"),
 		},
 		RecentTrades: []PublicTrade{
 			{
-				Amount:    1.64360163,
+				Amount:    MustParseDecimal("1.64360163"),
 				CreatedAt: wantTime1,
 				ID:        51362,
-				Price:     0.0191,
+				Price:     MustParseDecimal("0.0191"),
 			},
 			{
-				Amount:    1.60828469,
+				Amount:    MustParseDecimal("1.60828469"),
 				CreatedAt: wantTime2,
 				ID:        51362,
-				Price:     0.02248,
+				Price:     MustParseDecimal("0.02248"),
 			},
 		},
 	}
@@ -441,10 +441,10 @@ func TestClient_GetMarkets(t *testing.T) {
 			CanTrade:       true,
 			CanView:        true,
 			ID:             BIS_BTC,
-			MakerFee:       0.0025,
+			MakerFee:       MustParseDecimal("0.0025"),
 			MarketCurrency: BIS,
 			Metadata:       MarketMetadata{},
-			TakerFee:       0.0025,
+			TakerFee:       MustParseDecimal("0.0025"),
 		},
 		{
 			BaseCurrency:   BTC,
@@ -452,10 +452,10 @@ func TestClient_GetMarkets(t *testing.T) {
 			CanTrade:       true,
 			CanView:        true,
 			ID:             SNOW_BTC,
-			MakerFee:       0.0075,
+			MakerFee:       MustParseDecimal("0.0075"),
 			MarketCurrency: SNOW,
 			Metadata:       MarketMetadata{},
-			TakerFee:       0.0075,
+			TakerFee:       MustParseDecimal("0.0075"),
 		},
 	}
 
@@ -481,17 +481,17 @@ func TestClient_GetMarketTrades(t *testing.T) {
 
 	want := []PublicTrade{
 		{
-			Amount:      0.00760005,
+			Amount:      MustParseDecimal("0.00760005"),
 			CreatedAt:   wantTime,
 			ID:          51362,
-			Price:       0.01181539,
+			Price:       MustParseDecimal("0.01181539"),
 			SellerTaker: &b,
 		},
 		{
-			Amount:      4.99515615,
+			Amount:      MustParseDecimal("4.99515615"),
 			CreatedAt:   wantTime,
 			ID:          51354,
-			Price:       0.01180695,
+			Price:       MustParseDecimal("0.01180695"),
 			SellerTaker: &b,
 		},
 	}
@@ -513,6 +513,16 @@ func TestClient_GetOrderbook(t *testing.T) {
 		httpmock.NewStringResponder(200, orderbookTestData))
 
 	want := &Orderbook{
+		Bids: OrderbookLevels{
+			{Price: MustParseDecimal("0.00020139"), Amount: MustParseDecimal("100")},
+			{Price: MustParseDecimal("0.0001032"), Amount: MustParseDecimal("100")},
+			{Price: MustParseDecimal("0.000009"), Amount: MustParseDecimal("150")},
+		},
+		Asks: OrderbookLevels{
+			{Price: MustParseDecimal("0.02249"), Amount: MustParseDecimal("0.99720378")},
+			{Price: MustParseDecimal("5"), Amount: MustParseDecimal("100")},
+			{Price: MustParseDecimal("14"), Amount: MustParseDecimal("28")},
+		},
 		Buy: map[float64]float64{
 			0.000009:   150,
 			0.0001032:  100,
@@ -547,20 +557,20 @@ func TestClient_GetOHLCV(t *testing.T) {
 
 	want := []OHLCVSlice{
 		{
-			Close:  0.02,
-			High:   0.02,
-			Low:    0.02,
-			Open:   0.02,
+			Close:  MustParseDecimal("0.02"),
+			High:   MustParseDecimal("0.02"),
+			Low:    MustParseDecimal("0.02"),
+			Open:   MustParseDecimal("0.02"),
 			Time:   wantTime1,
-			Volume: 0.00190564,
+			Volume: MustParseDecimal("0.00190564"),
 		},
 		{
-			Close:  0.02,
-			High:   0.02,
-			Low:    0.02,
-			Open:   0.02,
+			Close:  MustParseDecimal("0.02"),
+			High:   MustParseDecimal("0.02"),
+			Low:    MustParseDecimal("0.02"),
+			Open:   MustParseDecimal("0.02"),
 			Time:   wantTime2,
-			Volume: 0,
+			Volume: MustParseDecimal("0"),
 		},
 	}
 
@@ -0,0 +1,290 @@
+package qtradetest
+
+import (
+	"sort"
+	"time"
+
+	qtrade "github.com/Henelik/qtrade-api-go/qtrade/v1"
+)
+
+type account struct {
+	keyID     string
+	key       string
+	info      qtrade.UserInfo
+	balances  map[qtrade.Currency]qtrade.Decimal
+	orders    []*bookOrder
+	trades    []qtrade.PrivateTrade
+	withdraws []qtrade.WithdrawDetails
+	deposits  []qtrade.DepositDetails
+}
+
+// bookOrder is an order together with the account that placed it.
+type bookOrder struct {
+	qtrade.Order
+	owner *account
+	// held is the base currency held per unit of a buy order, covering its
+	// price and the highest fee it could be charged.
+	held qtrade.Decimal
+}
+
+func (order *bookOrder) view() qtrade.Order {
+	result := order.Order
+	result.Trades = append([]qtrade.PrivateTrade{}, order.Trades...)
+
+	return result
+}
+
+// book holds the open orders of one market in price-time priority: best price
+// first, and oldest first within a price.
+type book struct {
+	bids       []*bookOrder
+	asks       []*bookOrder
+	trades     []qtrade.PublicTrade
+	lastChange int
+}
+
+func (b *book) insert(order *bookOrder) {
+	if order.OrderType == qtrade.BuyLimit {
+		i := sort.Search(len(b.bids), func(i int) bool { return b.bids[i].Price.Cmp(order.Price) < 0 })
+		b.bids = append(b.bids, nil)
+		copy(b.bids[i+1:], b.bids[i:])
+		b.bids[i] = order
+
+		return
+	}
+
+	i := sort.Search(len(b.asks), func(i int) bool { return b.asks[i].Price.Cmp(order.Price) > 0 })
+	b.asks = append(b.asks, nil)
+	copy(b.asks[i+1:], b.asks[i:])
+	b.asks[i] = order
+}
+
+// touch moves lastChange on to now in microseconds, like the real exchange,
+// while keeping it strictly increasing.
+func (b *book) touch(now time.Time) {
+	change := int(now.UnixNano() / int64(time.Microsecond))
+	if change <= b.lastChange {
+		change = b.lastChange + 1
+	}
+
+	b.lastChange = change
+}
+
+func (b *book) remove(order *bookOrder) {
+	b.bids = removeOrder(b.bids, order)
+	b.asks = removeOrder(b.asks, order)
+}
+
+func removeOrder(orders []*bookOrder, order *bookOrder) []*bookOrder {
+	for i, o := range orders {
+		if o == order {
+			return append(orders[:i], orders[i+1:]...)
+		}
+	}
+
+	return orders
+}
+
+// levels aggregates the remaining amount of orders at each price.
+func levels(orders []*bookOrder) map[qtrade.Decimal]qtrade.Decimal {
+	result := map[qtrade.Decimal]qtrade.Decimal{}
+	for _, order := range orders {
+		result[order.Price] = result[order.Price].Add(order.MarketAmountRemaining)
+	}
+
+	return result
+}
+
+// engine matches orders and settles the resulting trades between accounts.
+type engine struct {
+	books       map[qtrade.Market]*book
+	nextOrderID int
+	nextTradeID int
+	makerFee    qtrade.Decimal
+	takerFee    qtrade.Decimal
+}
+
+func newEngine(makerFee, takerFee qtrade.Decimal) *engine {
+	return &engine{
+		books:       map[qtrade.Market]*book{},
+		nextOrderID: 1,
+		nextTradeID: 1,
+		makerFee:    makerFee,
+		takerFee:    takerFee,
+	}
+}
+
+func (e *engine) book(market qtrade.Market) *book {
+	b, ok := e.books[market]
+	if !ok {
+		b = &book{}
+		e.books[market] = b
+	}
+
+	return b
+}
+
+// place holds the funds for a new order, matches it against the book and
+// rests whatever is left. It returns errInsufficientFunds if owner cannot pay
+// for the order.
+func (e *engine) place(owner *account, orderType qtrade.OrderType, market qtrade.Market,
+	amount, price qtrade.Decimal, now time.Time) (*bookOrder, error) {
+	order := &bookOrder{
+		Order: qtrade.Order{
+			CreatedAt:             now,
+			ID:                    e.nextOrderID,
+			MarketAmount:          amount,
+			MarketAmountRemaining: amount,
+			Market:                market,
+			Open:                  true,
+			OrderType:             orderType,
+			Price:                 price,
+			Trades:                []qtrade.PrivateTrade{},
+		},
+		owner: owner,
+	}
+
+	currency, held := market.MarketCurrency(), amount
+	if orderType == qtrade.BuyLimit {
+		fee := e.makerFee
+		if e.takerFee.Cmp(fee) > 0 {
+			fee = e.takerFee
+		}
+
+		order.held = price.Mul(fee.Add(qtrade.DecimalFromInt(1)))
+		currency, held = market.BaseCurrency(), amount.Mul(order.held)
+		order.BaseAmount = held
+	}
+
+	if held.Cmp(owner.balances[currency]) > 0 {
+		return nil, errInsufficientFunds
+	}
+
+	owner.balances[currency] = owner.balances[currency].Sub(held)
+	owner.orders = append(owner.orders, order)
+	e.nextOrderID++
+
+	b := e.book(market)
+	e.match(b, order, now)
+
+	if order.Open {
+		b.insert(order)
+	}
+
+	b.touch(now)
+
+	return order, nil
+}
+
+// match fills taker against resting orders on the other side of b, at the
+// resting orders' prices, for as long as the prices cross.
+func (e *engine) match(b *book, taker *bookOrder, now time.Time) {
+	for taker.Open {
+		var maker *bookOrder
+
+		if taker.OrderType == qtrade.BuyLimit {
+			if len(b.asks) == 0 || b.asks[0].Price.Cmp(taker.Price) > 0 {
+				return
+			}
+
+			maker = b.asks[0]
+		} else {
+			if len(b.bids) == 0 || b.bids[0].Price.Cmp(taker.Price) < 0 {
+				return
+			}
+
+			maker = b.bids[0]
+		}
+
+		amount := taker.MarketAmountRemaining
+		if maker.MarketAmountRemaining.Cmp(amount) < 0 {
+			amount = maker.MarketAmountRemaining
+		}
+
+		e.settle(b, maker, taker, amount, now)
+
+		if !maker.Open {
+			b.remove(maker)
+		}
+	}
+}
+
+// settle executes amount between a resting maker and an incoming taker,
+// moving funds and recording the trade for both accounts.
+func (e *engine) settle(b *book, maker, taker *bookOrder, amount qtrade.Decimal, now time.Time) {
+	price := maker.Price
+	value := amount.Mul(price)
+	id := e.nextTradeID
+	e.nextTradeID++
+
+	sellerTaker := taker.OrderType == qtrade.SellLimit
+
+	b.trades = append(b.trades, qtrade.PublicTrade{
+		Amount:      amount,
+		CreatedAt:   now,
+		ID:          id,
+		Price:       price,
+		SellerTaker: &sellerTaker,
+	})
+
+	e.fill(maker, amount, price, value.Mul(e.makerFee), id, false, now)
+	e.fill(taker, amount, price, value.Mul(e.takerFee), id, true, now)
+}
+
+func (e *engine) fill(order *bookOrder, amount, price, fee qtrade.Decimal, tradeID int, taker bool, now time.Time) {
+	owner := order.owner
+	market, base := order.Market.MarketCurrency(), order.Market.BaseCurrency()
+	value := amount.Mul(price)
+
+	side := "sell"
+	if order.OrderType == qtrade.BuyLimit {
+		side = "buy"
+		owner.balances[market] = owner.balances[market].Add(amount)
+		// release what was held for this amount beyond the actual cost
+		owner.balances[base] = owner.balances[base].Add(amount.Mul(order.held).Sub(value).Sub(fee))
+	} else {
+		owner.balances[base] = owner.balances[base].Add(value.Sub(fee))
+	}
+
+	trade := qtrade.PrivateTrade{
+		BaseAmount:   value,
+		BaseFee:      fee,
+		CreatedAt:    now,
+		ID:           tradeID,
+		OrderID:      order.ID,
+		Market:       order.Market,
+		MarketAmount: amount,
+		Price:        price,
+		Taker:        taker,
+		Side:         side,
+	}
+
+	owner.trades = append(owner.trades, trade)
+	order.Trades = append(order.Trades, trade)
+	order.MarketAmountRemaining = order.MarketAmountRemaining.Sub(amount)
+
+	if order.MarketAmountRemaining.IsZero() {
+		order.Open = false
+		order.CloseReason = "filled"
+	}
+}
+
+// cancel closes an open order and releases the funds it still holds.
+func (e *engine) cancel(order *bookOrder, now time.Time) {
+	owner := order.owner
+
+	if order.OrderType == qtrade.BuyLimit {
+		base := order.Market.BaseCurrency()
+		owner.balances[base] = owner.balances[base].Add(order.MarketAmountRemaining.Mul(order.held))
+	} else {
+		market := order.Market.MarketCurrency()
+		owner.balances[market] = owner.balances[market].Add(order.MarketAmountRemaining)
+	}
+
+	order.Open = false
+	order.CloseReason = "canceled"
+
+	b := e.book(order.Market)
+	b.remove(order)
+	b.touch(now)
+}
@@ -0,0 +1,762 @@
+// Package qtradetest provides fakes of the qTrade API for testing code that
+// uses the qtrade client, without HTTP mocks.
+package qtradetest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	qtrade "github.com/Henelik/qtrade-api-go/qtrade/v1"
+)
+
+// Fake is an in-memory implementation of qtrade.API. Public data is served
+// from its exported fields, while balances, orders and trades are tracked as
+// calls are made. Resting orders only trade when Fill is called, so tests
+// decide exactly when and how much of an order executes.
+//
+// A Fake is safe for concurrent use. Its exported fields should be set before
+// it is shared.
+type Fake struct {
+	UserInfo     qtrade.UserInfo
+	Currencies   []qtrade.CurrencyData
+	Markets      []qtrade.MarketData
+	Tickers      map[qtrade.Market]qtrade.Ticker
+	Orderbooks   map[qtrade.Market]qtrade.Orderbook
+	MarketTrades map[qtrade.Market][]qtrade.PublicTrade
+	OHLCV        map[qtrade.Market][]qtrade.OHLCVSlice
+	Deposits     []qtrade.DepositDetails
+	Transfers    []qtrade.Transfer
+
+	// Fee is charged in the base currency on every fill, as a fraction of the
+	// traded value.
+	Fee qtrade.Decimal
+
+	// Errors makes the method with the given name, such as "CreateBuyLimit",
+	// fail with the given error instead of doing anything.
+	Errors map[string]error
+
+	// Now returns the time used for new orders, trades and withdrawals. It
+	// defaults to time.Now.
+	Now func() time.Time
+
+	mu        sync.Mutex
+	balances  map[qtrade.Currency]qtrade.Decimal
+	orders    []*qtrade.Order
+	trades    []qtrade.PrivateTrade
+	withdraws []qtrade.WithdrawDetails
+	calls     map[string]int
+}
+
+var _ qtrade.API = (*Fake)(nil)
+
+// NewFake returns an empty Fake.
+func NewFake() *Fake {
+	return &Fake{
+		Tickers:      map[qtrade.Market]qtrade.Ticker{},
+		Orderbooks:   map[qtrade.Market]qtrade.Orderbook{},
+		MarketTrades: map[qtrade.Market][]qtrade.PublicTrade{},
+		OHLCV:        map[qtrade.Market][]qtrade.OHLCVSlice{},
+		Errors:       map[string]error{},
+		balances:     map[qtrade.Currency]qtrade.Decimal{},
+		calls:        map[string]int{},
+	}
+}
+
+// SetBalance sets the available balance of currency.
+func (f *Fake) SetBalance(currency qtrade.Currency, amount qtrade.Decimal) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.balances[currency] = amount
+}
+
+// Balance returns the available balance of currency, excluding funds held by open orders.
+func (f *Fake) Balance(currency qtrade.Currency) qtrade.Decimal {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.balances[currency]
+}
+
+// Calls returns how many times the method with the given name was called.
+func (f *Fake) Calls(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.calls[method]
+}
+
+// Fill executes amount of the open order with the given ID at its own price,
+// as if another user had traded against it. The order is closed once it has
+// been filled completely.
+func (f *Fake) Fill(id int, amount qtrade.Decimal) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	order := f.findOrder(id)
+	if order == nil {
+		return notFound(id)
+	}
+
+	if !order.Open {
+		return fmt.Errorf("order %v is not open", id)
+	}
+
+	if amount.Sign() <= 0 || amount.Cmp(order.MarketAmountRemaining) > 0 {
+		return fmt.Errorf("cannot fill %v of order %v with %v remaining", amount, id, order.MarketAmountRemaining)
+	}
+
+	market, base := order.Market.MarketCurrency(), order.Market.BaseCurrency()
+	value := amount.Mul(order.Price)
+	fee := value.Mul(f.Fee)
+
+	side := "sell"
+	if order.OrderType == qtrade.BuyLimit {
+		// the value and fee were already held when the order was placed
+		side = "buy"
+		f.balances[market] = f.balances[market].Add(amount)
+	} else {
+		f.balances[base] = f.balances[base].Add(value.Sub(fee))
+	}
+
+	trade := qtrade.PrivateTrade{
+		BaseAmount:   value,
+		BaseFee:      fee,
+		CreatedAt:    f.now(),
+		ID:           len(f.trades) + 1,
+		OrderID:      order.ID,
+		Market:       order.Market,
+		MarketAmount: amount,
+		Price:        order.Price,
+		Taker:        false,
+		Side:         side,
+	}
+
+	f.trades = append(f.trades, trade)
+	order.Trades = append(order.Trades, trade)
+	order.MarketAmountRemaining = order.MarketAmountRemaining.Sub(amount)
+
+	if order.MarketAmountRemaining.IsZero() {
+		order.Open = false
+		order.CloseReason = "filled"
+	}
+
+	return nil
+}
+
+func (f *Fake) now() time.Time {
+	if f.Now != nil {
+		return f.Now()
+	}
+
+	return time.Now()
+}
+
+// call records a call of method and returns the error configured for it, if any.
+// f.mu must be held.
+func (f *Fake) call(method string) error {
+	f.calls[method]++
+
+	return f.Errors[method]
+}
+
+func (f *Fake) findOrder(id int) *qtrade.Order {
+	for _, order := range f.orders {
+		if order.ID == id {
+			return order
+		}
+	}
+
+	return nil
+}
+
+func notFound(id int) error {
+	return apiError(http.StatusNotFound, qtrade.CodeOrderNotFound, fmt.Sprintf("Order %v not found", id))
+}
+
+func copyOrder(order *qtrade.Order) qtrade.Order {
+	result := *order
+	if order.Trades != nil {
+		result.Trades = append([]qtrade.PrivateTrade{}, order.Trades...)
+	}
+
+	return result
+}
+
+// idFilter applies the older_than and newer_than parameters to an ID.
+func idFilter(params map[string]string) (func(id int) bool, error) {
+	olderThan, newerThan := 0, 0
+
+	var err error
+
+	if v, ok := params["older_than"]; ok {
+		olderThan, err = strconv.Atoi(v)
+		if err != nil {
+			return nil, apiError(http.StatusBadRequest, "invalid_param", "older_than must be an integer")
+		}
+	}
+
+	if v, ok := params["newer_than"]; ok {
+		newerThan, err = strconv.Atoi(v)
+		if err != nil {
+			return nil, apiError(http.StatusBadRequest, "invalid_param", "newer_than must be an integer")
+		}
+	}
+
+	return func(id int) bool {
+		return (olderThan == 0 || id < olderThan) && id > newerThan
+	}, nil
+}
+
+func (f *Fake) GetCommon(ctx context.Context) (*qtrade.CommonData, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetCommon"); err != nil {
+		return nil, err
+	}
+
+	return &qtrade.CommonData{
+		Currencies: append([]qtrade.CurrencyData{}, f.Currencies...),
+		Markets:    append([]qtrade.MarketData{}, f.Markets...),
+		Tickers:    f.tickers(),
+	}, nil
+}
+
+func (f *Fake) GetTicker(ctx context.Context, market qtrade.Market) (*qtrade.Ticker, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetTicker"); err != nil {
+		return nil, err
+	}
+
+	ticker, ok := f.Tickers[market]
+	if !ok {
+		return nil, apiError(http.StatusBadRequest, "invalid_market", "Invalid market "+market.String())
+	}
+
+	return &ticker, nil
+}
+
+func (f *Fake) GetTickers(ctx context.Context) ([]qtrade.Ticker, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetTickers"); err != nil {
+		return nil, err
+	}
+
+	return f.tickers(), nil
+}
+
+func (f *Fake) tickers() []qtrade.Ticker {
+	tickers := make([]qtrade.Ticker, 0, len(f.Tickers))
+	for _, ticker := range f.Tickers {
+		tickers = append(tickers, ticker)
+	}
+
+	sort.Slice(tickers, func(i, j int) bool {
+		return tickers[i].Market < tickers[j].Market
+	})
+
+	return tickers
+}
+
+func (f *Fake) GetCurrency(ctx context.Context, currency qtrade.Currency) (*qtrade.CurrencyData, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetCurrency"); err != nil {
+		return nil, err
+	}
+
+	for _, data := range f.Currencies {
+		if data.Code == currency {
+			return &data, nil
+		}
+	}
+
+	return nil, apiError(http.StatusBadRequest, "invalid_currency", "Invalid currency "+string(currency))
+}
+
+func (f *Fake) GetCurrencies(ctx context.Context) ([]qtrade.CurrencyData, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetCurrencies"); err != nil {
+		return nil, err
+	}
+
+	return append([]qtrade.CurrencyData{}, f.Currencies...), nil
+}
+
+func (f *Fake) GetMarket(ctx context.Context, market qtrade.Market) (*qtrade.GetMarketData, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetMarket"); err != nil {
+		return nil, err
+	}
+
+	for _, data := range f.Markets {
+		if data.ID == market {
+			return &qtrade.GetMarketData{
+				Market:       data,
+				RecentTrades: append([]qtrade.PublicTrade{}, f.MarketTrades[market]...),
+			}, nil
+		}
+	}
+
+	return nil, apiError(http.StatusBadRequest, "invalid_market", "Invalid market "+market.String())
+}
+
+func (f *Fake) GetMarkets(ctx context.Context) ([]qtrade.MarketData, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetMarkets"); err != nil {
+		return nil, err
+	}
+
+	return append([]qtrade.MarketData{}, f.Markets...), nil
+}
+
+func (f *Fake) GetMarketTrades(ctx context.Context, market qtrade.Market) ([]qtrade.PublicTrade, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetMarketTrades"); err != nil {
+		return nil, err
+	}
+
+	return append([]qtrade.PublicTrade{}, f.MarketTrades[market]...), nil
+}
+
+// GetOrderbook returns the orderbook configured for market, or one built from
+// the Fake's own open orders if there is none.
+func (f *Fake) GetOrderbook(ctx context.Context, market qtrade.Market) (*qtrade.Orderbook, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetOrderbook"); err != nil {
+		return nil, err
+	}
+
+	if book, ok := f.Orderbooks[market]; ok {
+		if book.Bids == nil && book.Asks == nil {
+			return qtrade.NewOrderbook(floatLevels(book.Buy), floatLevels(book.Sell), book.LastChange), nil
+		}
+
+		return &book, nil
+	}
+
+	var bids, asks []qtrade.OrderbookLevel
+
+	for _, order := range f.orders {
+		if !order.Open || order.Market != market {
+			continue
+		}
+
+		level := qtrade.OrderbookLevel{Price: order.Price, Amount: order.MarketAmountRemaining}
+
+		if order.OrderType == qtrade.BuyLimit {
+			bids = append(bids, level)
+		} else {
+			asks = append(asks, level)
+		}
+	}
+
+	return qtrade.NewOrderbook(bids, asks, 0), nil
+}
+
+// floatLevels converts the levels of an orderbook configured with only the
+// Buy and Sell maps.
+func floatLevels(levels map[float64]float64) []qtrade.OrderbookLevel {
+	result := make([]qtrade.OrderbookLevel, 0, len(levels))
+	for price, amount := range levels {
+		result = append(result, qtrade.OrderbookLevel{
+			Price:  qtrade.DecimalFromFloat(price),
+			Amount: qtrade.DecimalFromFloat(amount),
+		})
+	}
+
+	return result
+}
+
+func (f *Fake) GetOHLCV(ctx context.Context, market qtrade.Market, interval qtrade.Interval, params map[string]string) ([]qtrade.OHLCVSlice, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetOHLCV"); err != nil {
+		return nil, err
+	}
+
+	return append([]qtrade.OHLCVSlice{}, f.OHLCV[market]...), nil
+}
+
+func (f *Fake) GetUserInfo(ctx context.Context) (*qtrade.UserInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetUserInfo"); err != nil {
+		return nil, err
+	}
+
+	info := f.UserInfo
+
+	return &info, nil
+}
+
+func (f *Fake) GetBalances(ctx context.Context, params map[string]string) ([]qtrade.Balance, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetBalances"); err != nil {
+		return nil, err
+	}
+
+	balances := make([]qtrade.Balance, 0, len(f.balances))
+	for currency, amount := range f.balances {
+		balances = append(balances, qtrade.Balance{
+			Currency: currency,
+			Balance:  amount,
+		})
+	}
+
+	sort.Slice(balances, func(i, j int) bool {
+		return balances[i].Currency < balances[j].Currency
+	})
+
+	return balances, nil
+}
+
+func (f *Fake) GetUserMarket(ctx context.Context, market qtrade.Market, params map[string]string) (*qtrade.UserMarketData, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetUserMarket"); err != nil {
+		return nil, err
+	}
+
+	data := &qtrade.UserMarketData{
+		BaseBalance:   f.balances[market.BaseCurrency()],
+		MarketBalance: f.balances[market.MarketCurrency()],
+		ClosedOrders:  []qtrade.Order{},
+		OpenOrders:    []qtrade.Order{},
+	}
+
+	for i := len(f.orders) - 1; i >= 0; i-- {
+		order := f.orders[i]
+		if order.Market != market {
+			continue
+		}
+
+		if order.Open {
+			data.OpenOrders = append(data.OpenOrders, copyOrder(order))
+		} else {
+			data.ClosedOrders = append(data.ClosedOrders, copyOrder(order))
+		}
+	}
+
+	return data, nil
+}
+
+// GetOrders returns orders newest first. It supports the open, older_than and
+// newer_than parameters.
+func (f *Fake) GetOrders(ctx context.Context, params map[string]string) ([]qtrade.Order, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetOrders"); err != nil {
+		return nil, err
+	}
+
+	matchID, err := idFilter(params)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := []qtrade.Order{}
+
+	for i := len(f.orders) - 1; i >= 0; i-- {
+		order := f.orders[i]
+
+		if open, ok := params["open"]; ok && strconv.FormatBool(order.Open) != open {
+			continue
+		}
+
+		if matchID(order.ID) {
+			orders = append(orders, copyOrder(order))
+		}
+	}
+
+	return orders, nil
+}
+
+func (f *Fake) GetOrder(ctx context.Context, id int) (*qtrade.Order, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetOrder"); err != nil {
+		return nil, err
+	}
+
+	order := f.findOrder(id)
+	if order == nil {
+		return nil, notFound(id)
+	}
+
+	result := copyOrder(order)
+
+	return &result, nil
+}
+
+// GetTrades returns trades newest first. It supports the older_than and
+// newer_than parameters.
+func (f *Fake) GetTrades(ctx context.Context, params map[string]string) ([]qtrade.PrivateTrade, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetTrades"); err != nil {
+		return nil, err
+	}
+
+	matchID, err := idFilter(params)
+	if err != nil {
+		return nil, err
+	}
+
+	trades := []qtrade.PrivateTrade{}
+
+	for i := len(f.trades) - 1; i >= 0; i-- {
+		if matchID(f.trades[i].ID) {
+			trades = append(trades, f.trades[i])
+		}
+	}
+
+	return trades, nil
+}
+
+func (f *Fake) CancelOrder(ctx context.Context, id int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("CancelOrder"); err != nil {
+		return err
+	}
+
+	order := f.findOrder(id)
+	if order == nil {
+		return notFound(id)
+	}
+
+	if !order.Open {
+		return apiError(http.StatusBadRequest, qtrade.CodeOrderClosed, fmt.Sprintf("Order %v is already closed", id))
+	}
+
+	if order.OrderType == qtrade.BuyLimit {
+		base := order.Market.BaseCurrency()
+		f.balances[base] = f.balances[base].Add(f.held(order.MarketAmountRemaining, order.Price))
+	} else {
+		market := order.Market.MarketCurrency()
+		f.balances[market] = f.balances[market].Add(order.MarketAmountRemaining)
+	}
+
+	order.Open = false
+	order.CloseReason = "canceled"
+
+	return nil
+}
+
+func (f *Fake) Withdraw(ctx context.Context, address string, amount qtrade.Decimal, currency qtrade.Currency) (*qtrade.WithdrawData, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("Withdraw"); err != nil {
+		return nil, err
+	}
+
+	if amount.Cmp(f.balances[currency]) > 0 {
+		return nil, apiError(http.StatusBadRequest, qtrade.CodeInsufficientFunds, "Insufficient funds")
+	}
+
+	f.balances[currency] = f.balances[currency].Sub(amount)
+
+	id := len(f.withdraws) + 1
+
+	f.withdraws = append(f.withdraws, qtrade.WithdrawDetails{
+		Address:     address,
+		Amount:      amount,
+		CreatedAt:   f.now(),
+		Currency:    currency,
+		ID:          id,
+		NetworkData: map[string]interface{}{},
+		Status:      "needs_create",
+		UserID:      f.UserInfo.ID,
+	})
+
+	return &qtrade.WithdrawData{
+		Code:   "initiated",
+		ID:     id,
+		Result: "Withdraw initiated. Please allow 3-5 minutes for our system to process.",
+	}, nil
+}
+
+func (f *Fake) GetWithdrawDetails(ctx context.Context, id int) (*qtrade.WithdrawDetails, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetWithdrawDetails"); err != nil {
+		return nil, err
+	}
+
+	for _, withdraw := range f.withdraws {
+		if withdraw.ID == id {
+			return &withdraw, nil
+		}
+	}
+
+	return nil, notFound(id)
+}
+
+func (f *Fake) GetWithdrawHistory(ctx context.Context, params map[string]string) ([]qtrade.WithdrawDetails, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetWithdrawHistory"); err != nil {
+		return nil, err
+	}
+
+	withdraws := make([]qtrade.WithdrawDetails, 0, len(f.withdraws))
+	for i := len(f.withdraws) - 1; i >= 0; i-- {
+		withdraws = append(withdraws, f.withdraws[i])
+	}
+
+	return withdraws, nil
+}
+
+func (f *Fake) GetDeposit(ctx context.Context, id string) ([]qtrade.DepositDetails, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetDeposit"); err != nil {
+		return nil, err
+	}
+
+	deposits := []qtrade.DepositDetails{}
+
+	for _, deposit := range f.Deposits {
+		if deposit.ID == id {
+			deposits = append(deposits, deposit)
+		}
+	}
+
+	return deposits, nil
+}
+
+func (f *Fake) GetDepositHistory(ctx context.Context, params map[string]string) ([]qtrade.DepositDetails, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetDepositHistory"); err != nil {
+		return nil, err
+	}
+
+	return append([]qtrade.DepositDetails{}, f.Deposits...), nil
+}
+
+func (f *Fake) GetDepositAddress(ctx context.Context, currency qtrade.Currency) (*qtrade.DepositAddressData, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetDepositAddress"); err != nil {
+		return nil, err
+	}
+
+	return &qtrade.DepositAddressData{
+		Address:        "fake-" + string(currency) + "-address",
+		CurrencyStatus: qtrade.CurrencyStatusOK,
+	}, nil
+}
+
+func (f *Fake) GetTransfers(ctx context.Context, params map[string]string) ([]qtrade.Transfer, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetTransfers"); err != nil {
+		return nil, err
+	}
+
+	return append([]qtrade.Transfer{}, f.Transfers...), nil
+}
+
+func (f *Fake) CreateSellLimit(ctx context.Context, amount qtrade.Decimal, market qtrade.Market, price qtrade.Decimal) (*qtrade.Order, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("CreateSellLimit"); err != nil {
+		return nil, err
+	}
+
+	return f.placeOrder(qtrade.SellLimit, amount, market, price)
+}
+
+func (f *Fake) CreateBuyLimit(ctx context.Context, amount qtrade.Decimal, market qtrade.Market, price qtrade.Decimal) (*qtrade.Order, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("CreateBuyLimit"); err != nil {
+		return nil, err
+	}
+
+	return f.placeOrder(qtrade.BuyLimit, amount, market, price)
+}
+
+// placeOrder holds the funds needed for a new order and records it as open.
+// f.mu must be held.
+func (f *Fake) placeOrder(orderType qtrade.OrderType, amount qtrade.Decimal, market qtrade.Market, price qtrade.Decimal) (*qtrade.Order, error) {
+	if amount.Sign() <= 0 || price.Sign() <= 0 {
+		return nil, apiError(http.StatusBadRequest, "invalid_amount", "Amount and price must be positive")
+	}
+
+	order := &qtrade.Order{
+		CreatedAt:             f.now(),
+		ID:                    len(f.orders) + 1,
+		MarketAmount:          amount,
+		MarketAmountRemaining: amount,
+		Market:                market,
+		Open:                  true,
+		OrderType:             orderType,
+		Price:                 price,
+		Trades:                []qtrade.PrivateTrade{},
+	}
+
+	held, currency := amount, market.MarketCurrency()
+	if orderType == qtrade.BuyLimit {
+		held, currency = f.held(amount, price), market.BaseCurrency()
+		order.BaseAmount = held
+	}
+
+	if held.Cmp(f.balances[currency]) > 0 {
+		return nil, apiError(http.StatusBadRequest, qtrade.CodeInsufficientFunds, "Insufficient funds")
+	}
+
+	f.balances[currency] = f.balances[currency].Sub(held)
+	f.orders = append(f.orders, order)
+
+	result := copyOrder(order)
+
+	return &result, nil
+}
+
+// held returns the base currency held by a buy order of amount at price,
+// covering its value and fee.
+func (f *Fake) held(amount, price qtrade.Decimal) qtrade.Decimal {
+	return amount.Mul(price).Mul(f.Fee.Add(qtrade.DecimalFromInt(1)))
+}
@@ -0,0 +1,194 @@
+package qtradetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	qtrade "github.com/Henelik/qtrade-api-go/qtrade/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+var dec = qtrade.MustParseDecimal
+
+func TestFake_OrderLifecycle(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	fake := NewFake()
+	fake.Now = func() time.Time { return now }
+	fake.SetBalance(qtrade.BTC, dec("1"))
+	fake.SetBalance(qtrade.LTC, dec("10"))
+
+	var api qtrade.API = fake
+
+	buy, err := api.CreateBuyLimit(ctx, dec("10"), qtrade.LTC_BTC, dec("0.05"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.True(t, buy.Open)
+	assert.Equal(t, dec("0.5"), fake.Balance(qtrade.BTC))
+
+	sell, err := api.CreateSellLimit(ctx, dec("4"), qtrade.LTC_BTC, dec("0.06"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, dec("6"), fake.Balance(qtrade.LTC))
+
+	assert.NoError(t, fake.Fill(buy.ID, dec("4")))
+	assert.NoError(t, fake.Fill(sell.ID, dec("4")))
+
+	gotBuy, err := api.GetOrder(ctx, buy.ID)
+	if assert.NoError(t, err) {
+		assert.True(t, gotBuy.Open)
+		assert.Equal(t, dec("6"), gotBuy.MarketAmountRemaining)
+		assert.Len(t, gotBuy.Trades, 1)
+	}
+
+	gotSell, err := api.GetOrder(ctx, sell.ID)
+	if assert.NoError(t, err) {
+		assert.False(t, gotSell.Open)
+		assert.Equal(t, "filled", gotSell.CloseReason)
+	}
+
+	assert.Equal(t, dec("10"), fake.Balance(qtrade.LTC))
+	assert.Equal(t, dec("0.74"), fake.Balance(qtrade.BTC))
+
+	assert.NoError(t, api.CancelOrder(ctx, buy.ID))
+	assert.Equal(t, dec("1.04"), fake.Balance(qtrade.BTC))
+
+	err = api.CancelOrder(ctx, buy.ID)
+	assert.Error(t, err)
+
+	open, err := api.GetOrders(ctx, map[string]string{"open": "true"})
+	if assert.NoError(t, err) {
+		assert.Empty(t, open)
+	}
+
+	trades, err := api.GetTrades(ctx, nil)
+	if assert.NoError(t, err) {
+		assert.Len(t, trades, 2)
+		assert.Equal(t, "sell", trades[0].Side)
+		assert.Equal(t, now, trades[0].CreatedAt)
+	}
+}
+
+func TestFake_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	fake := NewFake()
+	fake.SetBalance(qtrade.BTC, dec("0.1"))
+
+	_, err := fake.CreateBuyLimit(ctx, dec("10"), qtrade.LTC_BTC, dec("0.05"))
+	assert.True(t, qtrade.IsInsufficientFunds(err))
+
+	_, err = fake.GetOrder(ctx, 42)
+	assert.True(t, qtrade.IsOrderNotFound(err))
+
+	injected := errors.New("exchange is down")
+	fake.Errors["GetTickers"] = injected
+
+	_, err = fake.GetTickers(ctx)
+	assert.Equal(t, injected, err)
+	assert.Equal(t, 1, fake.Calls("GetTickers"))
+}
+
+func TestFake_GetOrderbook(t *testing.T) {
+	ctx := context.Background()
+
+	fake := NewFake()
+	fake.SetBalance(qtrade.BTC, dec("1"))
+	fake.SetBalance(qtrade.LTC, dec("10"))
+
+	_, _ = fake.CreateBuyLimit(ctx, dec("2"), qtrade.LTC_BTC, dec("0.05"))
+	_, _ = fake.CreateBuyLimit(ctx, dec("3"), qtrade.LTC_BTC, dec("0.05"))
+	_, _ = fake.CreateSellLimit(ctx, dec("1"), qtrade.LTC_BTC, dec("0.07"))
+
+	book, err := fake.GetOrderbook(ctx, qtrade.LTC_BTC)
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[float64]float64{0.05: 5}, book.Buy)
+		assert.Equal(t, map[float64]float64{0.07: 1}, book.Sell)
+		assert.Equal(t, qtrade.OrderbookLevels{{Price: dec("0.05"), Amount: dec("5")}}, book.Bids)
+	}
+
+	// books configured with only the float maps get their levels filled in
+	fake.Orderbooks[qtrade.ETH_BTC] = qtrade.Orderbook{Sell: map[float64]float64{0.03: 2}}
+
+	book, err = fake.GetOrderbook(ctx, qtrade.ETH_BTC)
+	if assert.NoError(t, err) {
+		assert.Equal(t, qtrade.OrderbookLevels{{Price: dec("0.03"), Amount: dec("2")}}, book.Asks)
+	}
+}
+
+func TestFake_CancelAllOrders(t *testing.T) {
+	ctx := context.Background()
+
+	fake := NewFake()
+	fake.SetBalance(qtrade.BTC, dec("1"))
+	fake.SetBalance(qtrade.LTC, dec("10"))
+
+	buy1, err := fake.CreateBuyLimit(ctx, dec("1"), qtrade.LTC_BTC, dec("0.01"))
+	assert.NoError(t, err)
+
+	buy2, err := fake.CreateBuyLimit(ctx, dec("1"), qtrade.LTC_BTC, dec("0.02"))
+	assert.NoError(t, err)
+
+	sell, err := fake.CreateSellLimit(ctx, dec("1"), qtrade.LTC_BTC, dec("0.05"))
+	assert.NoError(t, err)
+
+	report, err := qtrade.CancelAllOrders(ctx, fake, qtrade.CancelFilter{
+		Market: qtrade.LTC_BTC,
+		Side:   qtrade.BuyLimit,
+		Verify: true,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.True(t, report.OK())
+	assert.Len(t, report.Canceled, 2)
+	assert.Equal(t, 2, fake.Calls("CancelOrder"))
+
+	for _, id := range []int{buy1.ID, buy2.ID, sell.ID} {
+		order, err := fake.GetOrder(ctx, id)
+		if assert.NoError(t, err) {
+			assert.Equal(t, id == sell.ID, order.Open)
+		}
+	}
+}
+
+func TestFake_WaitForOrder(t *testing.T) {
+	ctx := context.Background()
+
+	fake := NewFake()
+	fake.SetBalance(qtrade.BTC, dec("1"))
+
+	order, err := fake.CreateBuyLimit(ctx, dec("10"), qtrade.LTC_BTC, dec("0.05"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, fake.Fill(order.ID, dec("4")))
+
+	var kinds []qtrade.OrderEventKind
+
+	final, err := qtrade.WaitForOrder(ctx, fake, order.ID, qtrade.WaitOptions{
+		MinInterval: time.Millisecond,
+		OnEvent: func(event qtrade.OrderEvent) {
+			kinds = append(kinds, event.Kind)
+
+			if event.Kind == qtrade.OrderPartiallyFilled {
+				assert.NoError(t, fake.Fill(order.ID, event.Order.MarketAmountRemaining))
+			}
+		},
+	})
+	if assert.NoError(t, err) {
+		assert.False(t, final.Open)
+		assert.True(t, final.MarketAmountRemaining.IsZero())
+	}
+
+	assert.Equal(t, []qtrade.OrderEventKind{qtrade.OrderPartiallyFilled, qtrade.OrderFilled}, kinds)
+}
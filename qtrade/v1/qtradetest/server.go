@@ -0,0 +1,833 @@
+package qtradetest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	qtrade "github.com/Henelik/qtrade-api-go/qtrade/v1"
+)
+
+// maxClockSkew is how far the HMAC-Timestamp of a request may be from the
+// server's clock before the signature is rejected.
+const maxClockSkew = time.Second * 30
+
+var (
+	errInsufficientFunds = apiError(http.StatusBadRequest, qtrade.CodeInsufficientFunds, "Insufficient funds")
+	errInvalidAuth       = apiError(http.StatusForbidden, qtrade.CodeInvalidAuth, "Invalid HMAC signature")
+	errNotFound          = apiError(http.StatusNotFound, qtrade.CodeNotFound, "Not found")
+)
+
+// Server is a local fake of the qTrade v1 HTTP API, for integration tests of
+// qtrade.Client and the code built on it. It checks request signatures the
+// way the exchange does, and runs a price-time priority matching engine over
+// the orders of all its accounts, producing fills, fees and balance changes.
+//
+// Create accounts with NewAccount, fund them with SetBalance or Deposit, and
+// point a client at the server with Configuration.
+type Server struct {
+	*httptest.Server
+
+	// Markets and Currencies are served by the public endpoints. Orders are
+	// accepted on any market known to the qtrade package, listed or not.
+	Markets    []qtrade.MarketData
+	Currencies []qtrade.CurrencyData
+
+	// Now returns the time used for new orders and trades. It defaults to time.Now.
+	Now func() time.Time
+
+	mu       sync.Mutex
+	engine   *engine
+	accounts map[string]*account
+}
+
+// NewServer starts a Server without accounts and without fees. It must be
+// closed when no longer needed.
+func NewServer() *Server {
+	s := &Server{
+		engine:   newEngine(qtrade.Decimal{}, qtrade.Decimal{}),
+		accounts: map[string]*account{},
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// SetFees sets the fees charged on fills, as fractions of the traded value in
+// the base currency.
+func (s *Server) SetFees(makerFee, takerFee qtrade.Decimal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.engine.makerFee = makerFee
+	s.engine.takerFee = takerFee
+}
+
+// NewAccount creates an account with no funds and returns its HMAC keypair.
+func (s *Server) NewAccount() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+
+	id := len(s.accounts) + 1
+
+	acc := &account{
+		keyID:    strconv.Itoa(id),
+		key:      hex.EncodeToString(key),
+		balances: map[qtrade.Currency]qtrade.Decimal{},
+		info: qtrade.UserInfo{
+			CanLogin:    true,
+			CanTrade:    true,
+			CanWithdraw: true,
+			Email:       fmt.Sprintf("user%v@example.com", id),
+			ID:          id,
+		},
+	}
+
+	s.accounts[acc.keyID] = acc
+
+	return acc.keyID + ":" + acc.key
+}
+
+// Configuration returns a client configuration for the account with keypair.
+func (s *Server) Configuration(keypair string) qtrade.Configuration {
+	return qtrade.Configuration{
+		HMACKeypair: keypair,
+		Endpoint:    s.URL,
+		Timeout:     time.Second * 10,
+	}
+}
+
+// SetBalance sets the available balance of currency for the account with keypair.
+func (s *Server) SetBalance(keypair string, currency qtrade.Currency, amount qtrade.Decimal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.mustAccount(keypair).balances[currency] = amount
+}
+
+// Balance returns the available balance of currency for the account with
+// keypair, excluding funds held by open orders.
+func (s *Server) Balance(keypair string, currency qtrade.Currency) qtrade.Decimal {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.mustAccount(keypair).balances[currency]
+}
+
+// Deposit credits amount of currency to the account with keypair, and records
+// it in the account's deposit history.
+func (s *Server) Deposit(keypair string, currency qtrade.Currency, amount qtrade.Decimal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc := s.mustAccount(keypair)
+	acc.balances[currency] = acc.balances[currency].Add(amount)
+	acc.deposits = append(acc.deposits, qtrade.DepositDetails{
+		Address:     depositAddress(currency),
+		Amount:      amount,
+		CreatedAt:   s.now(),
+		Currency:    currency,
+		ID:          fmt.Sprintf("%v:%v", acc.keyID, len(acc.deposits)+1),
+		NetworkData: map[string]interface{}{},
+		Status:      "credited",
+	})
+}
+
+func (s *Server) mustAccount(keypair string) *account {
+	acc, ok := s.accounts[strings.SplitN(keypair, ":", 2)[0]]
+	if !ok {
+		panic("qtradetest: unknown account " + keypair)
+	}
+
+	return acc
+}
+
+func (s *Server) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+
+	return time.Now()
+}
+
+func depositAddress(currency qtrade.Currency) string {
+	return "fake-" + string(currency) + "-address"
+}
+
+func apiError(status int, code, title string) *qtrade.APIError {
+	return &qtrade.APIError{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%v %s", status, http.StatusText(status)),
+		Errors:     []qtrade.Error{{Code: code, Title: title}},
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	apiErr, ok := err.(*qtrade.APIError)
+	if !ok {
+		apiErr = apiError(http.StatusInternalServerError, "internal_error", err.Error())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(apiErr.StatusCode)
+	_ = json.NewEncoder(w).Encode(qtrade.ErrorResult{Errors: apiErr.Errors})
+}
+
+func writeData(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+// authenticate checks the HMAC signature of r and returns the signing account.
+func (s *Server) authenticate(r *http.Request, body []byte) (*account, error) {
+	auth := strings.TrimPrefix(r.Header.Get("Authorization"), "HMAC-SHA256 ")
+
+	parts := strings.SplitN(auth, ":", 2)
+	if len(parts) != 2 {
+		return nil, errInvalidAuth
+	}
+
+	acc, ok := s.accounts[parts[0]]
+	if !ok {
+		return nil, errInvalidAuth
+	}
+
+	timestamp := r.Header.Get("HMAC-Timestamp")
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, errInvalidAuth
+	}
+
+	if skew := time.Since(time.Unix(seconds, 0)); skew > maxClockSkew || skew < -maxClockSkew {
+		return nil, errInvalidAuth
+	}
+
+	reqDetails := bytes.NewBufferString(r.Method)
+	reqDetails.WriteString("\n")
+	reqDetails.WriteString(r.URL.RequestURI())
+	reqDetails.WriteString("\n")
+	reqDetails.WriteString(timestamp)
+	reqDetails.WriteString("\n")
+	reqDetails.Write(body)
+	reqDetails.WriteString("\n")
+	reqDetails.WriteString(acc.key)
+
+	hash := sha256.Sum256(reqDetails.Bytes())
+	want := base64.StdEncoding.EncodeToString(hash[:])
+
+	if subtle.ConstantTimeCompare([]byte(want), []byte(parts[1])) != 1 {
+		return nil, errInvalidAuth
+	}
+
+	return acc, nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/"), "/")
+
+	var data interface{}
+
+	if path[0] == "user" {
+		acc, authErr := s.authenticate(r, body)
+		if authErr != nil {
+			writeError(w, authErr)
+			return
+		}
+
+		data, err = s.servePrivate(r, acc, path[1:], body)
+	} else {
+		data, err = s.servePublic(r, path)
+	}
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeData(w, data)
+}
+
+// route reports whether the request matches method and the path pattern,
+// where "*" matches any single segment.
+func route(r *http.Request, path []string, method string, pattern ...string) bool {
+	if r.Method != method || len(path) != len(pattern) {
+		return false
+	}
+
+	for i, segment := range pattern {
+		if segment != "*" && segment != path[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *Server) servePublic(r *http.Request, path []string) (interface{}, error) {
+	switch {
+	case route(r, path, "GET", "common"):
+		return qtrade.CommonData{
+			Currencies: s.currencies(),
+			Markets:    s.markets(),
+			Tickers:    s.tickers(),
+		}, nil
+
+	case route(r, path, "GET", "ticker", "*"):
+		market, err := parseMarket(path[1])
+		if err != nil {
+			return nil, err
+		}
+
+		return s.ticker(market), nil
+
+	case route(r, path, "GET", "tickers"):
+		return map[string]interface{}{"markets": s.tickers()}, nil
+
+	case route(r, path, "GET", "currency", "*"):
+		for _, currency := range s.currencies() {
+			if string(currency.Code) == path[1] {
+				return map[string]interface{}{"currency": currency}, nil
+			}
+		}
+
+		return nil, errNotFound
+
+	case route(r, path, "GET", "currencies"):
+		return map[string]interface{}{"currencies": s.currencies()}, nil
+
+	case route(r, path, "GET", "market", "*"):
+		market, err := parseMarket(path[1])
+		if err != nil {
+			return nil, err
+		}
+
+		return qtrade.GetMarketData{
+			Market:       s.market(market),
+			RecentTrades: s.publicTrades(market),
+		}, nil
+
+	case route(r, path, "GET", "markets"):
+		return map[string]interface{}{"markets": s.markets()}, nil
+
+	case route(r, path, "GET", "market", "*", "trades"):
+		market, err := parseMarket(path[1])
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{"trades": s.publicTrades(market)}, nil
+
+	case route(r, path, "GET", "orderbook", "*"):
+		market, err := parseMarket(path[1])
+		if err != nil {
+			return nil, err
+		}
+
+		b := s.engine.book(market)
+
+		return qtrade.GetOrderbookData{
+			Buy:        formatLevels(levels(b.bids)),
+			LastChange: b.lastChange,
+			Sell:       formatLevels(levels(b.asks)),
+		}, nil
+
+	case route(r, path, "GET", "market", "*", "ohlcv", "*"):
+		market, err := parseMarket(path[1])
+		if err != nil {
+			return nil, err
+		}
+
+		slices, err := filterOHLCV(s.ohlcv(market, qtrade.Interval(path[3])), r.URL.Query())
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{"slices": slices}, nil
+	}
+
+	return nil, errNotFound
+}
+
+func (s *Server) servePrivate(r *http.Request, acc *account, path []string, body []byte) (interface{}, error) {
+	query := r.URL.Query()
+
+	switch {
+	case route(r, path, "GET", "me"):
+		return map[string]interface{}{"user": acc.info}, nil
+
+	case route(r, path, "GET", "balances"):
+		return map[string]interface{}{"balances": balances(acc)}, nil
+
+	case route(r, path, "GET", "market", "*"):
+		market, err := parseMarket(path[1])
+		if err != nil {
+			return nil, err
+		}
+
+		data := qtrade.UserMarketData{
+			BaseBalance:   acc.balances[market.BaseCurrency()],
+			MarketBalance: acc.balances[market.MarketCurrency()],
+			ClosedOrders:  []qtrade.Order{},
+			OpenOrders:    []qtrade.Order{},
+		}
+
+		for i := len(acc.orders) - 1; i >= 0; i-- {
+			order := acc.orders[i]
+
+			switch {
+			case order.Market != market:
+			case order.Open:
+				data.OpenOrders = append(data.OpenOrders, order.view())
+			default:
+				data.ClosedOrders = append(data.ClosedOrders, order.view())
+			}
+		}
+
+		return data, nil
+
+	case route(r, path, "GET", "orders"):
+		matchID, err := idFilter(flatten(query))
+		if err != nil {
+			return nil, err
+		}
+
+		orders := []qtrade.Order{}
+
+		for i := len(acc.orders) - 1; i >= 0; i-- {
+			order := acc.orders[i]
+
+			if open := query.Get("open"); open != "" && strconv.FormatBool(order.Open) != open {
+				continue
+			}
+
+			if matchID(order.ID) {
+				orders = append(orders, order.view())
+			}
+		}
+
+		return map[string]interface{}{"orders": orders}, nil
+
+	case route(r, path, "GET", "order", "*"):
+		order, err := findOrder(acc, path[1])
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{"order": order.view()}, nil
+
+	case route(r, path, "GET", "trades"):
+		matchID, err := idFilter(flatten(query))
+		if err != nil {
+			return nil, err
+		}
+
+		trades := []qtrade.PrivateTrade{}
+
+		for i := len(acc.trades) - 1; i >= 0; i-- {
+			if matchID(acc.trades[i].ID) {
+				trades = append(trades, acc.trades[i])
+			}
+		}
+
+		return map[string]interface{}{"trades": trades}, nil
+
+	case route(r, path, "POST", "cancel_order"):
+		return s.cancelOrder(acc, body)
+
+	case route(r, path, "POST", "withdraw"):
+		return s.withdraw(acc, body)
+
+	case route(r, path, "GET", "withdraw", "*"):
+		for _, withdraw := range acc.withdraws {
+			if strconv.Itoa(withdraw.ID) == path[1] {
+				return map[string]interface{}{"withdraw": withdraw}, nil
+			}
+		}
+
+		return nil, errNotFound
+
+	case route(r, path, "GET", "withdraws"):
+		withdraws := []qtrade.WithdrawDetails{}
+		for i := len(acc.withdraws) - 1; i >= 0; i-- {
+			withdraws = append(withdraws, acc.withdraws[i])
+		}
+
+		return map[string]interface{}{"withdraws": withdraws}, nil
+
+	case route(r, path, "GET", "deposit", "*"):
+		deposits := []qtrade.DepositDetails{}
+
+		for _, deposit := range acc.deposits {
+			if deposit.ID == path[1] {
+				deposits = append(deposits, deposit)
+			}
+		}
+
+		return map[string]interface{}{"deposit": deposits}, nil
+
+	case route(r, path, "GET", "deposits"):
+		deposits := []qtrade.DepositDetails{}
+		for i := len(acc.deposits) - 1; i >= 0; i-- {
+			deposits = append(deposits, acc.deposits[i])
+		}
+
+		return map[string]interface{}{"deposits": deposits}, nil
+
+	case route(r, path, "POST", "deposit_address", "*"):
+		return qtrade.DepositAddressData{
+			Address:        depositAddress(qtrade.Currency(path[1])),
+			CurrencyStatus: qtrade.CurrencyStatusOK,
+		}, nil
+
+	case route(r, path, "GET", "transfers"):
+		return map[string]interface{}{"transfers": []qtrade.Transfer{}}, nil
+
+	case route(r, path, "POST", "sell_limit"):
+		return s.placeOrder(acc, qtrade.SellLimit, body)
+
+	case route(r, path, "POST", "buy_limit"):
+		return s.placeOrder(acc, qtrade.BuyLimit, body)
+	}
+
+	return nil, errNotFound
+}
+
+func (s *Server) placeOrder(acc *account, orderType qtrade.OrderType, body []byte) (interface{}, error) {
+	var req struct {
+		Amount   qtrade.Decimal `json:"amount"`
+		MarketID int            `json:"market_id"`
+		Price    qtrade.Decimal `json:"price"`
+	}
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, apiError(http.StatusBadRequest, "invalid_request", "Invalid request body")
+	}
+
+	market := qtrade.Market(req.MarketID)
+	if market.MarketCurrency() == "" {
+		return nil, apiError(http.StatusBadRequest, "invalid_market", "Invalid market")
+	}
+
+	if req.Amount.Sign() <= 0 || req.Price.Sign() <= 0 {
+		return nil, apiError(http.StatusBadRequest, "invalid_amount", "Amount and price must be positive numbers")
+	}
+
+	order, err := s.engine.place(acc, orderType, market, req.Amount, req.Price, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{"order": order.view()}, nil
+}
+
+func (s *Server) cancelOrder(acc *account, body []byte) (interface{}, error) {
+	var req struct {
+		ID int `json:"id"`
+	}
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, apiError(http.StatusBadRequest, "invalid_request", "Invalid request body")
+	}
+
+	order, err := findOrder(acc, strconv.Itoa(req.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	if !order.Open {
+		return nil, apiError(http.StatusBadRequest, qtrade.CodeOrderClosed, fmt.Sprintf("Order %v is already closed", req.ID))
+	}
+
+	s.engine.cancel(order, s.now())
+
+	return map[string]interface{}{}, nil
+}
+
+func (s *Server) withdraw(acc *account, body []byte) (interface{}, error) {
+	var req struct {
+		Address  string          `json:"address"`
+		Amount   qtrade.Decimal  `json:"amount"`
+		Currency qtrade.Currency `json:"currency"`
+	}
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, apiError(http.StatusBadRequest, "invalid_request", "Invalid request body")
+	}
+
+	if req.Amount.Sign() <= 0 {
+		return nil, apiError(http.StatusBadRequest, "invalid_amount", "Amount must be a positive number")
+	}
+
+	if req.Amount.Cmp(acc.balances[req.Currency]) > 0 {
+		return nil, errInsufficientFunds
+	}
+
+	acc.balances[req.Currency] = acc.balances[req.Currency].Sub(req.Amount)
+
+	id := len(acc.withdraws) + 1
+
+	acc.withdraws = append(acc.withdraws, qtrade.WithdrawDetails{
+		Address:     req.Address,
+		Amount:      req.Amount,
+		CreatedAt:   s.now(),
+		Currency:    req.Currency,
+		ID:          id,
+		NetworkData: map[string]interface{}{},
+		Status:      "needs_create",
+		UserID:      acc.info.ID,
+	})
+
+	return qtrade.WithdrawData{
+		Code:   "initiated",
+		ID:     id,
+		Result: "Withdraw initiated. Please allow 3-5 minutes for our system to process.",
+	}, nil
+}
+
+func findOrder(acc *account, id string) (*bookOrder, error) {
+	for _, order := range acc.orders {
+		if strconv.Itoa(order.ID) == id {
+			return order, nil
+		}
+	}
+
+	return nil, apiError(http.StatusNotFound, qtrade.CodeOrderNotFound, "Order "+id+" not found")
+}
+
+func balances(acc *account) []qtrade.Balance {
+	result := make([]qtrade.Balance, 0, len(acc.balances))
+	for currency, amount := range acc.balances {
+		result = append(result, qtrade.Balance{
+			Currency: currency,
+			Balance:  amount,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Currency < result[j].Currency
+	})
+
+	return result
+}
+
+func (s *Server) currencies() []qtrade.CurrencyData {
+	return append([]qtrade.CurrencyData{}, s.Currencies...)
+}
+
+func (s *Server) markets() []qtrade.MarketData {
+	return append([]qtrade.MarketData{}, s.Markets...)
+}
+
+// market returns the listed data of market, or defaults for an unlisted one.
+func (s *Server) market(market qtrade.Market) qtrade.MarketData {
+	for _, data := range s.Markets {
+		if data.ID == market {
+			return data
+		}
+	}
+
+	return qtrade.MarketData{
+		BaseCurrency:   market.BaseCurrency(),
+		CanCancel:      true,
+		CanTrade:       true,
+		CanView:        true,
+		ID:             market,
+		MakerFee:       s.engine.makerFee,
+		MarketCurrency: market.MarketCurrency(),
+		TakerFee:       s.engine.takerFee,
+	}
+}
+
+func (s *Server) ticker(market qtrade.Market) qtrade.Ticker {
+	b := s.engine.book(market)
+	ticker := qtrade.Ticker{
+		Market: market,
+		IDHr:   market.String(),
+	}
+
+	if len(b.bids) > 0 {
+		ticker.Bid = b.bids[0].Price
+	}
+
+	if len(b.asks) > 0 {
+		ticker.Ask = b.asks[0].Price
+	}
+
+	if len(b.trades) > 0 {
+		ticker.Last = b.trades[len(b.trades)-1].Price
+	}
+
+	return ticker
+}
+
+func (s *Server) tickers() []qtrade.Ticker {
+	markets := make([]qtrade.Market, 0, len(s.engine.books))
+	for market := range s.engine.books {
+		markets = append(markets, market)
+	}
+
+	sort.Slice(markets, func(i, j int) bool { return markets[i] < markets[j] })
+
+	tickers := make([]qtrade.Ticker, len(markets))
+	for i, market := range markets {
+		tickers[i] = s.ticker(market)
+	}
+
+	return tickers
+}
+
+// publicTrades returns the trades of market, newest first.
+func (s *Server) publicTrades(market qtrade.Market) []qtrade.PublicTrade {
+	trades := s.engine.book(market).trades
+
+	result := make([]qtrade.PublicTrade, 0, len(trades))
+	for i := len(trades) - 1; i >= 0; i-- {
+		result = append(result, trades[i])
+	}
+
+	return result
+}
+
+// ohlcv buckets the trades of market into slices of interval, oldest first.
+func (s *Server) ohlcv(market qtrade.Market, interval qtrade.Interval) []qtrade.OHLCVSlice {
+	slices := []qtrade.OHLCVSlice{}
+
+	duration := interval.Duration()
+	if duration == 0 {
+		return slices
+	}
+
+	for _, trade := range s.engine.book(market).trades {
+		start := trade.CreatedAt.Truncate(duration)
+
+		if n := len(slices); n > 0 && slices[n-1].Time.Equal(start) {
+			slice := &slices[n-1]
+			slice.Close = trade.Price
+			slice.Volume = slice.Volume.Add(trade.Amount.Mul(trade.Price))
+
+			if trade.Price.Cmp(slice.High) > 0 {
+				slice.High = trade.Price
+			}
+
+			if trade.Price.Cmp(slice.Low) < 0 {
+				slice.Low = trade.Price
+			}
+
+			continue
+		}
+
+		slices = append(slices, qtrade.OHLCVSlice{
+			Close:  trade.Price,
+			High:   trade.Price,
+			Low:    trade.Price,
+			Open:   trade.Price,
+			Time:   start,
+			Volume: trade.Amount.Mul(trade.Price),
+		})
+	}
+
+	return slices
+}
+
+// filterOHLCV applies the start, end and limit parameters to slices, which
+// are sorted oldest first. It returns the newest slices when more match, and
+// never more than qtrade.OHLCVLimit.
+func filterOHLCV(slices []qtrade.OHLCVSlice, query url.Values) ([]qtrade.OHLCVSlice, error) {
+	params := map[string]int64{"start": 0, "end": 0, "limit": qtrade.OHLCVLimit}
+
+	for key := range params {
+		if v := query.Get(key); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, apiError(http.StatusBadRequest, "invalid_param", key+" must be an integer")
+			}
+
+			params[key] = n
+		}
+	}
+
+	if params["limit"] <= 0 || params["limit"] > qtrade.OHLCVLimit {
+		params["limit"] = qtrade.OHLCVLimit
+	}
+
+	result := []qtrade.OHLCVSlice{}
+
+	for _, slice := range slices {
+		if params["start"] != 0 && slice.Time.Unix() < params["start"] {
+			continue
+		}
+
+		if params["end"] != 0 && slice.Time.Unix() >= params["end"] {
+			continue
+		}
+
+		result = append(result, slice)
+	}
+
+	if n := int64(len(result)); n > params["limit"] {
+		result = result[n-params["limit"]:]
+	}
+
+	return result, nil
+}
+
+func formatLevels(levels map[qtrade.Decimal]qtrade.Decimal) map[string]string {
+	result := make(map[string]string, len(levels))
+	for price, amount := range levels {
+		result[price.String()] = amount.String()
+	}
+
+	return result
+}
+
+func flatten(values map[string][]string) map[string]string {
+	result := make(map[string]string, len(values))
+	for k, v := range values {
+		if len(v) > 0 {
+			result[k] = v[0]
+		}
+	}
+
+	return result
+}
+
+// parseMarket resolves a market known to the qtrade package from its name,
+// such as LTC_BTC, or its ID.
+func parseMarket(s string) (qtrade.Market, error) {
+	market, err := qtrade.ParseMarket(s)
+	if err != nil {
+		return 0, apiError(http.StatusBadRequest, "invalid_market", "Invalid market "+s)
+	}
+
+	return market, nil
+}
@@ -0,0 +1,271 @@
+package qtradetest
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	qtrade "github.com/Henelik/qtrade-api-go/qtrade/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestClient(t *testing.T, server *Server, keypair string) *qtrade.Client {
+	client, err := qtrade.NewClient(server.Configuration(keypair))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+func TestServer_Matching(t *testing.T) {
+	ctx := context.Background()
+
+	server := NewServer()
+	defer server.Close()
+
+	server.SetFees(dec("0"), dec("0.01"))
+
+	makerKeys, takerKeys := server.NewAccount(), server.NewAccount()
+	server.SetBalance(makerKeys, qtrade.LTC, dec("10"))
+	server.SetBalance(takerKeys, qtrade.BTC, dec("1"))
+
+	maker := newTestClient(t, server, makerKeys)
+	taker := newTestClient(t, server, takerKeys)
+
+	ask1, err := maker.CreateSellLimit(ctx, dec("2"), qtrade.LTC_BTC, dec("0.05"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ask2, err := maker.CreateSellLimit(ctx, dec("3"), qtrade.LTC_BTC, dec("0.04"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ask3, err := maker.CreateSellLimit(ctx, dec("1"), qtrade.LTC_BTC, dec("0.05"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	book, err := taker.GetOrderbook(ctx, qtrade.LTC_BTC)
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[float64]float64{0.04: 3, 0.05: 3}, book.Sell)
+		assert.Empty(t, book.Buy)
+	}
+
+	// crosses the best ask completely, then the oldest order at the next price
+	bid, err := taker.CreateBuyLimit(ctx, dec("4"), qtrade.LTC_BTC, dec("0.05"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.False(t, bid.Open)
+	assert.Equal(t, "filled", bid.CloseReason)
+
+	if assert.Len(t, bid.Trades, 2) {
+		assert.Equal(t, dec("0.04"), bid.Trades[0].Price)
+		assert.Equal(t, dec("3"), bid.Trades[0].MarketAmount)
+		assert.Equal(t, dec("0.05"), bid.Trades[1].Price)
+		assert.Equal(t, dec("1"), bid.Trades[1].MarketAmount)
+		assert.True(t, bid.Trades[0].Taker)
+		assert.Equal(t, dec("0.0012"), bid.Trades[0].BaseFee)
+	}
+
+	// the taker paid 0.17 plus a 1% fee, and got back the rest of what was held
+	assert.Equal(t, dec("4"), server.Balance(takerKeys, qtrade.LTC))
+	assert.Equal(t, dec("0.8283"), server.Balance(takerKeys, qtrade.BTC))
+	assert.Equal(t, dec("0.17"), server.Balance(makerKeys, qtrade.BTC))
+
+	gotAsk1, err := maker.GetOrder(ctx, ask1.ID)
+	if assert.NoError(t, err) {
+		assert.True(t, gotAsk1.Open)
+		assert.Equal(t, dec("1"), gotAsk1.MarketAmountRemaining)
+	}
+
+	gotAsk2, err := maker.GetOrder(ctx, ask2.ID)
+	if assert.NoError(t, err) {
+		assert.False(t, gotAsk2.Open)
+	}
+
+	assert.NoError(t, maker.CancelOrder(ctx, ask3.ID))
+	assert.Equal(t, dec("5"), server.Balance(makerKeys, qtrade.LTC))
+
+	_, err = taker.GetOrder(ctx, ask1.ID)
+	assert.True(t, qtrade.IsOrderNotFound(err))
+
+	trades, err := maker.GetTrades(ctx, nil)
+	if assert.NoError(t, err) {
+		assert.Len(t, trades, 2)
+		assert.Equal(t, "sell", trades[0].Side)
+		assert.False(t, trades[0].Taker)
+	}
+
+	marketTrades, err := taker.GetMarketTrades(ctx, qtrade.LTC_BTC)
+	if assert.NoError(t, err) && assert.Len(t, marketTrades, 2) {
+		assert.False(t, *marketTrades[0].SellerTaker)
+	}
+
+	ticker, err := taker.GetTicker(ctx, qtrade.LTC_BTC)
+	if assert.NoError(t, err) {
+		assert.Equal(t, dec("0.05"), ticker.Ask)
+		assert.Equal(t, dec("0.05"), ticker.Last)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	server := NewServer()
+	defer server.Close()
+
+	keys := server.NewAccount()
+	client := newTestClient(t, server, keys)
+
+	_, err := client.CreateBuyLimit(ctx, dec("1"), qtrade.LTC_BTC, dec("0.05"))
+	assert.True(t, qtrade.IsInsufficientFunds(err))
+
+	forged, err := qtrade.NewClient(server.Configuration(keys[:2] + "0000"))
+	if assert.NoError(t, err) {
+		_, err = forged.GetBalances(ctx, nil)
+		assert.True(t, qtrade.IsUnauthorized(err))
+	}
+
+	err = client.CancelOrder(ctx, 42)
+	assert.True(t, qtrade.IsOrderNotFound(err))
+}
+
+func TestServer_Accounts(t *testing.T) {
+	ctx := context.Background()
+
+	server := NewServer()
+	defer server.Close()
+
+	keys := server.NewAccount()
+	server.Deposit(keys, qtrade.BTC, dec("0.5"))
+
+	client := newTestClient(t, server, keys)
+
+	balances, err := client.GetBalances(ctx, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []qtrade.Balance{{Currency: qtrade.BTC, Balance: dec("0.5")}}, balances)
+	}
+
+	deposits, err := client.GetDepositHistory(ctx, nil)
+	if assert.NoError(t, err) && assert.Len(t, deposits, 1) {
+		assert.Equal(t, dec("0.5"), deposits[0].Amount)
+	}
+
+	withdraw, err := client.Withdraw(ctx, "abcd", dec("0.2"), qtrade.BTC)
+	if assert.NoError(t, err) {
+		details, err := client.GetWithdrawDetails(ctx, withdraw.ID)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "abcd", details.Address)
+		}
+	}
+
+	assert.Equal(t, dec("0.3"), server.Balance(keys, qtrade.BTC))
+
+	info, err := client.GetUserInfo(ctx)
+	if assert.NoError(t, err) {
+		assert.True(t, info.CanTrade)
+	}
+}
+
+// recordingTransport keeps the bodies of the responses it receives.
+type recordingTransport struct {
+	bodies []string
+}
+
+func (transport *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	transport.bodies = append(transport.bodies, string(body))
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return resp, nil
+}
+
+func TestServer_WireFormat(t *testing.T) {
+	ctx := context.Background()
+
+	server := NewServer()
+	defer server.Close()
+
+	keys := server.NewAccount()
+	server.SetBalance(keys, qtrade.BTC, dec("1"))
+
+	transport := new(recordingTransport)
+
+	client, err := qtrade.NewClient(server.Configuration(keys), qtrade.WithTransport(transport))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = client.CreateBuyLimit(ctx, dec("1"), qtrade.LTC_BTC, dec("0.05"))
+	assert.NoError(t, err)
+
+	_, err = client.GetTicker(ctx, qtrade.LTC_BTC)
+	assert.NoError(t, err)
+
+	// the order is preceded by the check that its market is tradable
+	if assert.Len(t, transport.bodies, 3) {
+		// the API identifies markets by integer ID
+		assert.Regexp(t, `"id":1[,}]`, transport.bodies[0])
+		assert.Regexp(t, `"market_id":1[,}]`, transport.bodies[1])
+		assert.Regexp(t, `"id":1[,}]`, transport.bodies[2])
+	}
+
+	for _, body := range transport.bodies {
+		assert.NotContains(t, body, `"market_id":"`)
+		assert.NotContains(t, body, `"id":"`)
+	}
+}
+
+func TestServer_OHLCVLimit(t *testing.T) {
+	ctx := context.Background()
+
+	server := NewServer()
+	defer server.Close()
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	count := qtrade.OHLCVLimit*2 + 500
+
+	book := server.engine.book(qtrade.LTC_BTC)
+	for i := 0; i < count; i++ {
+		book.trades = append(book.trades, qtrade.PublicTrade{
+			Amount:    dec("1"),
+			CreatedAt: start.Add(time.Duration(i) * time.Hour),
+			ID:        i + 1,
+			Price:     dec("0.01"),
+		})
+	}
+
+	client := qtrade.NewPublicClient(qtrade.Configuration{Endpoint: server.URL})
+	end := start.Add(time.Duration(count) * time.Hour)
+
+	// a single request is cut short at the cap
+	slices, err := client.QueryOHLCV(ctx, qtrade.LTC_BTC, qtrade.OneHour, qtrade.OHLCVQuery{Start: start, End: end, Limit: count})
+	if assert.NoError(t, err) {
+		assert.Len(t, slices, qtrade.OHLCVLimit)
+	}
+
+	slices, err = client.FetchOHLCVRange(ctx, qtrade.LTC_BTC, qtrade.OneHour, start, end)
+	if assert.NoError(t, err) && assert.Len(t, slices, count) {
+		assert.Equal(t, start, slices[0].Time.UTC())
+		assert.Equal(t, end.Add(-time.Hour), slices[count-1].Time.UTC())
+	}
+}
@@ -0,0 +1,212 @@
+package qtrade
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// Query is a typed set of request parameters that encodes itself into the
+// map accepted by the Get methods.
+type Query interface {
+	Params() map[string]string
+}
+
+var (
+	_ Query = OrdersQuery{}
+	_ Query = TradesQuery{}
+	_ Query = BalancesQuery{}
+	_ Query = UserMarketQuery{}
+	_ Query = HistoryQuery{}
+	_ Query = OHLCVQuery{}
+)
+
+// OrdersQuery holds the parameters of GetOrders.
+type OrdersQuery struct {
+	// Open restricts the results to open orders if true, or closed orders if
+	// false. All orders are returned if it is nil.
+	Open *bool
+	// OlderThan and NewerThan restrict the results to orders with IDs below
+	// and above them, when set.
+	OlderThan int
+	NewerThan int
+	// Market restricts the results to one market, when set.
+	Market Market
+	// Extra holds parameters that have no field.
+	Extra map[string]string
+}
+
+func (q OrdersQuery) Params() map[string]string {
+	params := newParams(q.Extra)
+	setBool(params, "open", q.Open)
+	setInt(params, "older_than", q.OlderThan)
+	setInt(params, "newer_than", q.NewerThan)
+	setMarket(params, q.Market)
+
+	return params
+}
+
+// TradesQuery holds the parameters of GetTrades.
+type TradesQuery struct {
+	// OlderThan and NewerThan restrict the results to trades with IDs below
+	// and above them, when set.
+	OlderThan int
+	NewerThan int
+	// Desc sorts the results newest first.
+	Desc bool
+	// Market restricts the results to one market, when set.
+	Market Market
+	// Extra holds parameters that have no field.
+	Extra map[string]string
+}
+
+func (q TradesQuery) Params() map[string]string {
+	params := newParams(q.Extra)
+	setInt(params, "older_than", q.OlderThan)
+	setInt(params, "newer_than", q.NewerThan)
+	setMarket(params, q.Market)
+
+	if q.Desc {
+		params["desc"] = "true"
+	}
+
+	return params
+}
+
+// BalancesQuery holds the parameters of GetBalances, which has none
+// documented.
+type BalancesQuery struct {
+	Extra map[string]string
+}
+
+func (q BalancesQuery) Params() map[string]string {
+	return newParams(q.Extra)
+}
+
+// UserMarketQuery holds the parameters of GetUserMarket, which has none
+// documented.
+type UserMarketQuery struct {
+	Extra map[string]string
+}
+
+func (q UserMarketQuery) Params() map[string]string {
+	return newParams(q.Extra)
+}
+
+// HistoryQuery holds the parameters of GetWithdrawHistory, GetDepositHistory
+// and GetTransfers.
+type HistoryQuery struct {
+	// OlderThan and NewerThan restrict the results to records with IDs below
+	// and above them, when set.
+	OlderThan int
+	NewerThan int
+	// Extra holds parameters that have no field.
+	Extra map[string]string
+}
+
+func (q HistoryQuery) Params() map[string]string {
+	params := newParams(q.Extra)
+	setInt(params, "older_than", q.OlderThan)
+	setInt(params, "newer_than", q.NewerThan)
+
+	return params
+}
+
+// OHLCVQuery holds the parameters of GetOHLCV.
+type OHLCVQuery struct {
+	// Start and End bound the time of the returned slices, when set.
+	Start time.Time
+	End   time.Time
+	// Limit caps the number of slices returned, when set.
+	Limit int
+	// Extra holds parameters that have no field.
+	Extra map[string]string
+}
+
+func (q OHLCVQuery) Params() map[string]string {
+	params := newParams(q.Extra)
+	setTime(params, "start", q.Start)
+	setTime(params, "end", q.End)
+	setInt(params, "limit", q.Limit)
+
+	return params
+}
+
+func newParams(extra map[string]string) map[string]string {
+	params := make(map[string]string, len(extra))
+	for k, v := range extra {
+		params[k] = v
+	}
+
+	return params
+}
+
+func setBool(params map[string]string, key string, value *bool) {
+	if value != nil {
+		params[key] = strconv.FormatBool(*value)
+	}
+}
+
+func setInt(params map[string]string, key string, value int) {
+	if value != 0 {
+		params[key] = strconv.Itoa(value)
+	}
+}
+
+func setTime(params map[string]string, key string, value time.Time) {
+	if !value.IsZero() {
+		params[key] = strconv.FormatInt(value.Unix(), 10)
+	}
+}
+
+// setMarket filters by market name, or by ID for markets unknown to this
+// package.
+func setMarket(params map[string]string, market Market) {
+	switch {
+	case market == 0:
+	case market.MarketCurrency() != "":
+		params["market_string"] = market.String()
+	default:
+		params["market_id"] = strconv.Itoa(int(market))
+	}
+}
+
+// QueryOrders is GetOrders with typed parameters.
+func (client *Client) QueryOrders(ctx context.Context, q OrdersQuery) ([]Order, error) {
+	return client.GetOrders(ctx, q.Params())
+}
+
+// QueryTrades is GetTrades with typed parameters.
+func (client *Client) QueryTrades(ctx context.Context, q TradesQuery) ([]PrivateTrade, error) {
+	return client.GetTrades(ctx, q.Params())
+}
+
+// QueryBalances is GetBalances with typed parameters.
+func (client *Client) QueryBalances(ctx context.Context, q BalancesQuery) ([]Balance, error) {
+	return client.GetBalances(ctx, q.Params())
+}
+
+// QueryUserMarket is GetUserMarket with typed parameters.
+func (client *Client) QueryUserMarket(ctx context.Context, market Market, q UserMarketQuery) (*UserMarketData, error) {
+	return client.GetUserMarket(ctx, market, q.Params())
+}
+
+// QueryWithdrawHistory is GetWithdrawHistory with typed parameters.
+func (client *Client) QueryWithdrawHistory(ctx context.Context, q HistoryQuery) ([]WithdrawDetails, error) {
+	return client.GetWithdrawHistory(ctx, q.Params())
+}
+
+// QueryDepositHistory is GetDepositHistory with typed parameters.
+func (client *Client) QueryDepositHistory(ctx context.Context, q HistoryQuery) ([]DepositDetails, error) {
+	return client.GetDepositHistory(ctx, q.Params())
+}
+
+// QueryTransfers is GetTransfers with typed parameters.
+func (client *Client) QueryTransfers(ctx context.Context, q HistoryQuery) ([]Transfer, error) {
+	return client.GetTransfers(ctx, q.Params())
+}
+
+// QueryOHLCV is GetOHLCV with typed parameters.
+func (client *Client) QueryOHLCV(ctx context.Context, market Market, interval Interval, q OHLCVQuery) ([]OHLCVSlice, error) {
+	return client.GetOHLCV(ctx, market, interval, q.Params())
+}
@@ -0,0 +1,108 @@
+package qtrade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_Params(t *testing.T) {
+	open := true
+	closed := false
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name  string
+		query Query
+		want  map[string]string
+	}{
+		{
+			name:  "empty orders query",
+			query: OrdersQuery{},
+			want:  map[string]string{},
+		},
+		{
+			name:  "open orders",
+			query: OrdersQuery{Open: &open, OlderThan: 13252, Market: LTC_BTC},
+			want:  map[string]string{"open": "true", "older_than": "13252", "market_string": "LTC_BTC"},
+		},
+		{
+			name:  "closed orders in an unknown market",
+			query: OrdersQuery{Open: &closed, NewerThan: 100, Market: Market(999)},
+			want:  map[string]string{"open": "false", "newer_than": "100", "market_id": "999"},
+		},
+		{
+			name:  "trades",
+			query: TradesQuery{NewerThan: 63286, Desc: true, Market: BTC_pUSD},
+			want:  map[string]string{"newer_than": "63286", "desc": "true", "market_string": "BTC_pUSD"},
+		},
+		{
+			name:  "history",
+			query: HistoryQuery{OlderThan: 9},
+			want:  map[string]string{"older_than": "9"},
+		},
+		{
+			name:  "ohlcv",
+			query: OHLCVQuery{Start: start, End: start.Add(time.Hour), Limit: 12},
+			want:  map[string]string{"start": "1577836800", "end": "1577840400", "limit": "12"},
+		},
+		{
+			name:  "extra parameters",
+			query: BalancesQuery{Extra: map[string]string{"foo": "bar"}},
+			want:  map[string]string{"foo": "bar"},
+		},
+		{
+			name:  "fields override extra parameters",
+			query: HistoryQuery{NewerThan: 2, Extra: map[string]string{"newer_than": "1", "foo": "bar"}},
+			want:  map[string]string{"newer_than": "2", "foo": "bar"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.query.Params())
+		})
+	}
+}
+
+func TestQuery_DoesNotModifyExtra(t *testing.T) {
+	extra := map[string]string{"foo": "bar"}
+
+	UserMarketQuery{Extra: extra}.Params()["foo"] = "baz"
+	OrdersQuery{Open: new(bool), Extra: extra}.Params()
+
+	assert.Equal(t, map[string]string{"foo": "bar"}, extra)
+}
+
+func TestClient_QueryOrders(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/user/orders?market_string=LTC_BTC&older_than=13300&open=true",
+		httpmock.NewStringResponder(200, ordersTestData))
+
+	open := true
+
+	got, err := testClient.QueryOrders(context.Background(), OrdersQuery{Open: &open, OlderThan: 13300, Market: LTC_BTC})
+	if assert.NoError(t, err) {
+		assert.Len(t, got, 2)
+	}
+
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET http://localhost/v1/user/orders?market_string=LTC_BTC&older_than=13300&open=true"])
+}
+
+func TestClient_QueryOHLCV(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/market/LTC_BTC/ohlcv/fourhour?limit=1000",
+		httpmock.NewStringResponder(200, ohlcvTestData))
+
+	_, err := testClient.QueryOHLCV(context.Background(), LTC_BTC, FourHour, OHLCVQuery{Limit: 1000})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET http://localhost/v1/market/LTC_BTC/ohlcv/fourhour?limit=1000"])
+}
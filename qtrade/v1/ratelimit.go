@@ -0,0 +1,151 @@
+package qtrade
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultRateLimitWindow is how long the limiter holds off after a 429
+// response that did not say when the window resets.
+const defaultRateLimitWindow = time.Second
+
+// assumedRateLimitWindow is how long the limiter assumes a window lasts when
+// it refills a known limit by itself, because the current window ended before
+// any response described the next one. The API only reports the time left in
+// a window, not its length, so the next response corrects the guess.
+const assumedRateLimitWindow = time.Second
+
+// rateLimiter is a token bucket shared by every request made through a Client.
+// It is refilled from the x-ratelimit-* headers of each response: the API
+// reports how many calls are left in the current window and how many seconds
+// remain until the window resets. Once the bucket is empty, callers wait for
+// the reset instead of running into a 429.
+type rateLimiter struct {
+	mu     sync.Mutex
+	known  bool      // whether a response has described the current window
+	tokens int       // calls left in the current window
+	limit  int       // calls allowed per window, if the API reported it
+	reset  time.Time // when the current window ends
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{}
+}
+
+// wait blocks until a call may be made, or until ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+
+	for {
+		l.mu.Lock()
+
+		if !l.known {
+			l.mu.Unlock()
+			return ctx.Err()
+		}
+
+		if l.tokens > 0 {
+			l.tokens--
+			l.mu.Unlock()
+			return ctx.Err()
+		}
+
+		reset := l.reset
+		delay := time.Until(reset)
+
+		if delay <= 0 {
+			l.refill(reset)
+			l.mu.Unlock()
+			continue
+		}
+
+		l.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		l.mu.Lock()
+		l.refill(reset)
+		l.mu.Unlock()
+	}
+}
+
+// refill starts a new window, unless a response has already moved the window
+// on since reset was read. l.mu must be held.
+func (l *rateLimiter) refill(reset time.Time) {
+	if !l.reset.Equal(reset) {
+		return
+	}
+
+	if l.limit > 0 {
+		l.tokens = l.limit
+		l.reset = time.Now().Add(assumedRateLimitWindow)
+
+		return
+	}
+
+	// without a known limit, let the next response describe the new window
+	l.known = false
+}
+
+// update refreshes the bucket from the rate limit headers of resp.
+func (l *rateLimiter) update(resp *http.Response) {
+	if l == nil || resp == nil {
+		return
+	}
+
+	remaining, remainingErr := strconv.Atoi(resp.Header.Get("x-ratelimit-remaining"))
+	resetSeconds, resetErr := strconv.Atoi(resp.Header.Get("x-ratelimit-reset"))
+	limit, limitErr := strconv.Atoi(resp.Header.Get("x-ratelimit-limit"))
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limitErr == nil {
+		l.limit = limit
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		remaining, remainingErr = 0, nil
+
+		if resetErr != nil {
+			resetSeconds, resetErr = int(defaultRateLimitWindow/time.Second), nil
+		}
+	}
+
+	if remainingErr != nil || resetErr != nil {
+		return
+	}
+
+	reset := time.Now().Add(time.Duration(resetSeconds) * time.Second)
+
+	// Responses to concurrent requests can arrive out of order. Within the
+	// same window, only ever lower the count, since calls may already be in
+	// flight that the server has not seen yet.
+	if l.known && !reset.After(l.reset.Add(time.Second)) {
+		if remaining < l.tokens {
+			l.tokens = remaining
+		}
+
+		if reset.After(l.reset) {
+			l.reset = reset
+		}
+
+		return
+	}
+
+	l.known = true
+	l.tokens = remaining
+	l.reset = reset
+}
@@ -0,0 +1,152 @@
+package qtrade
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rateLimitResponse(status int, remaining, reset string) *http.Response {
+	header := http.Header{}
+	header.Set("x-ratelimit-remaining", remaining)
+	header.Set("x-ratelimit-reset", reset)
+
+	return &http.Response{StatusCode: status, Header: header}
+}
+
+func TestRateLimiter_wait(t *testing.T) {
+	testCases := []struct {
+		name      string
+		resp      *http.Response
+		calls     int
+		wantDelay bool
+	}{
+		{
+			name:  "unknown window does not block",
+			resp:  nil,
+			calls: 3,
+		},
+		{
+			name:  "remaining calls do not block",
+			resp:  rateLimitResponse(200, "3", "1"),
+			calls: 3,
+		},
+		{
+			name:      "empty bucket waits for reset",
+			resp:      rateLimitResponse(200, "1", "1"),
+			calls:     2,
+			wantDelay: true,
+		},
+		{
+			name:      "too many requests empties the bucket",
+			resp:      rateLimitResponse(429, "", "1"),
+			calls:     1,
+			wantDelay: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			limiter := newRateLimiter()
+			limiter.update(tc.resp)
+
+			start := time.Now()
+
+			for i := 0; i < tc.calls; i++ {
+				assert.NoError(t, limiter.wait(context.Background()))
+			}
+
+			assert.Equal(t, tc.wantDelay, time.Since(start) > time.Millisecond*500)
+		})
+	}
+}
+
+func TestRateLimiter_waitContext(t *testing.T) {
+	limiter := newRateLimiter()
+	limiter.update(rateLimitResponse(429, "0", "60"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	start := time.Now()
+
+	err := limiter.wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+}
+
+func TestRateLimiter_refillKnownLimit(t *testing.T) {
+	resp := rateLimitResponse(200, "0", "0")
+	resp.Header.Set("x-ratelimit-limit", "2")
+
+	limiter := newRateLimiter()
+	limiter.update(resp)
+
+	start := time.Now()
+
+	assert.NoError(t, limiter.wait(context.Background()))
+	assert.NoError(t, limiter.wait(context.Background()))
+	assert.Less(t, int64(time.Since(start)), int64(time.Millisecond*500))
+
+	assert.Equal(t, 0, limiter.tokens)
+	assert.WithinDuration(t, start.Add(assumedRateLimitWindow), limiter.reset, time.Millisecond*500)
+}
+
+func TestRateLimiter_updateKeepsLowestCount(t *testing.T) {
+	limiter := newRateLimiter()
+	limiter.update(rateLimitResponse(200, "5", "10"))
+	limiter.update(rateLimitResponse(200, "2", "10"))
+	limiter.update(rateLimitResponse(200, "4", "10"))
+
+	assert.Equal(t, 2, limiter.tokens)
+}
+
+func TestClient_RateLimitConcurrent(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		w.Header().Set("x-ratelimit-remaining", "0")
+		w.Header().Set("x-ratelimit-reset", "60")
+		w.Write([]byte(userTestData))
+	}))
+
+	defer server.Close()
+
+	limitedClient, _ := NewClient(
+		Configuration{
+			HMACKeypair: "1:1111111111111111111111111111111111111111111111111111111111111111",
+			Endpoint:    server.URL,
+			Timeout:     time.Second * 10,
+		})
+
+	_, err := limitedClient.GetUserInfo(context.Background())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := limitedClient.GetUserInfo(ctx)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
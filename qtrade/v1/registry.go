@@ -0,0 +1,309 @@
+package qtrade
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// MarketInfo describes a market as listed by the exchange.
+type MarketInfo struct {
+	ID             Market
+	Name           string
+	MarketCurrency Currency
+	BaseCurrency   Currency
+	MakerFee       Decimal
+	TakerFee       Decimal
+	// MarketPrecision and BasePrecision are the decimal places of the market
+	// and base currencies, used for order amounts and prices respectively.
+	// They are UnknownPrecision when the currency is neither listed nor in
+	// CurrencyDecimalPlaces.
+	MarketPrecision int
+	BasePrecision   int
+	CanTrade        bool
+	CanCancel       bool
+	CanView         bool
+}
+
+// UnknownPrecision is the precision of a MarketInfo whose currency precision
+// is not known.
+const UnknownPrecision = -1
+
+// CurrencyInfo describes a currency as listed by the exchange.
+type CurrencyInfo struct {
+	Code        Currency
+	LongName    string
+	Precision   int
+	CanWithdraw bool
+	Status      CurrencyStatus
+	WithdrawFee Decimal
+}
+
+// Registry holds the markets and currencies listed by the exchange, loaded
+// from /v1/common. Unlike the Market and Currency constants, it knows about
+// pairs listed after this package was released.
+//
+// A Registry is safe for concurrent use. Attach it to a Client with
+// WithRegistry so that requests use its market names and precisions.
+type Registry struct {
+	// OnError, if set, is called with the errors of refreshes made by Run.
+	OnError func(error)
+
+	mu         sync.RWMutex
+	markets    map[Market]MarketInfo
+	names      map[string]Market
+	currencies map[Currency]CurrencyInfo
+	updatedAt  time.Time
+}
+
+// NewRegistry returns an empty Registry. Fill it with Load or Refresh.
+func NewRegistry() *Registry {
+	return &Registry{
+		markets:    map[Market]MarketInfo{},
+		names:      map[string]Market{},
+		currencies: map[Currency]CurrencyInfo{},
+	}
+}
+
+// LoadRegistry returns a Registry filled from api.
+func LoadRegistry(ctx context.Context, api PublicAPI) (*Registry, error) {
+	registry := NewRegistry()
+
+	if err := registry.Refresh(ctx, api); err != nil {
+		return nil, err
+	}
+
+	return registry, nil
+}
+
+// Refresh replaces the contents of the registry with the markets and currencies
+// currently listed by api. On error the previous contents are kept.
+func (registry *Registry) Refresh(ctx context.Context, api PublicAPI) error {
+	common, err := api.GetCommon(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to refresh registry")
+	}
+
+	registry.Load(common)
+
+	return nil
+}
+
+// Load replaces the contents of the registry with data.
+func (registry *Registry) Load(data *CommonData) {
+	currencies := make(map[Currency]CurrencyInfo, len(data.Currencies))
+	for _, currency := range data.Currencies {
+		currencies[currency.Code] = CurrencyInfo{
+			Code:        currency.Code,
+			LongName:    currency.LongName,
+			Precision:   currency.Precision,
+			CanWithdraw: currency.CanWithdraw,
+			Status:      currency.Status,
+			WithdrawFee: currency.Config.WithdrawFee,
+		}
+	}
+
+	markets := make(map[Market]MarketInfo, len(data.Markets))
+	names := make(map[string]Market, len(data.Markets))
+
+	for _, market := range data.Markets {
+		info := MarketInfo{
+			ID:              market.ID,
+			Name:            string(market.MarketCurrency) + "_" + string(market.BaseCurrency),
+			MarketCurrency:  market.MarketCurrency,
+			BaseCurrency:    market.BaseCurrency,
+			MakerFee:        market.MakerFee,
+			TakerFee:        market.TakerFee,
+			MarketPrecision: UnknownPrecision,
+			BasePrecision:   UnknownPrecision,
+			CanTrade:        market.CanTrade,
+			CanCancel:       market.CanCancel,
+			CanView:         market.CanView,
+		}
+
+		if places, ok := precision(currencies, market.MarketCurrency); ok {
+			info.MarketPrecision = places
+		}
+
+		if places, ok := precision(currencies, market.BaseCurrency); ok {
+			info.BasePrecision = places
+		}
+
+		markets[info.ID] = info
+		names[strings.ToUpper(info.Name)] = info.ID
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.markets = markets
+	registry.names = names
+	registry.currencies = currencies
+	registry.updatedAt = time.Now()
+}
+
+// precision returns the listed precision of currency, falling back to
+// CurrencyDecimalPlaces for currencies missing from the listing, and reports
+// whether it is known.
+func precision(currencies map[Currency]CurrencyInfo, currency Currency) (int, bool) {
+	if info, ok := currencies[currency]; ok {
+		return info.Precision, true
+	}
+
+	places, ok := CurrencyDecimalPlaces[currency]
+
+	return places, ok
+}
+
+// Run refreshes the registry from api every interval until ctx is done, and
+// returns the context's error. The registry is refreshed immediately if it has
+// never been loaded. Failed refreshes keep the previous contents and are
+// reported to OnError.
+func (registry *Registry) Run(ctx context.Context, api PublicAPI, interval time.Duration) error {
+	if registry.UpdatedAt().IsZero() {
+		registry.refresh(ctx, api)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			registry.refresh(ctx, api)
+		}
+	}
+}
+
+func (registry *Registry) refresh(ctx context.Context, api PublicAPI) {
+	if err := registry.Refresh(ctx, api); err != nil && registry.OnError != nil && ctx.Err() == nil {
+		registry.OnError(err)
+	}
+}
+
+// UpdatedAt returns when the registry was last loaded, or the zero time if it
+// never was.
+func (registry *Registry) UpdatedAt() time.Time {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	return registry.updatedAt
+}
+
+// Market returns the market with the given ID.
+func (registry *Registry) Market(id Market) (MarketInfo, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	info, ok := registry.markets[id]
+
+	return info, ok
+}
+
+// MarketByName returns the market with the given name, such as "LTC_BTC". The
+// name is not case sensitive.
+func (registry *Registry) MarketByName(name string) (MarketInfo, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	id, ok := registry.names[strings.ToUpper(name)]
+	if !ok {
+		return MarketInfo{}, false
+	}
+
+	return registry.markets[id], true
+}
+
+// ParseMarket returns the market with the given name or numeric ID, like the
+// ParseMarket function, but also accepts the markets listed by the registry.
+func (registry *Registry) ParseMarket(s string) (Market, error) {
+	if info, ok := registry.MarketByName(s); ok {
+		return info.ID, nil
+	}
+
+	if market, err := ParseMarketID(s); err == nil {
+		if _, ok := registry.Market(market); ok {
+			return market, nil
+		}
+	}
+
+	return ParseMarket(s)
+}
+
+// Currency returns the currency with the given code.
+func (registry *Registry) Currency(code Currency) (CurrencyInfo, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	info, ok := registry.currencies[code]
+
+	return info, ok
+}
+
+// Markets returns all listed markets, ordered by ID.
+func (registry *Registry) Markets() []MarketInfo {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	markets := make([]MarketInfo, 0, len(registry.markets))
+	for _, info := range registry.markets {
+		markets = append(markets, info)
+	}
+
+	sort.Slice(markets, func(i, j int) bool { return markets[i].ID < markets[j].ID })
+
+	return markets
+}
+
+// Currencies returns all listed currencies, ordered by code.
+func (registry *Registry) Currencies() []CurrencyInfo {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	currencies := make([]CurrencyInfo, 0, len(registry.currencies))
+	for _, info := range registry.currencies {
+		currencies = append(currencies, info)
+	}
+
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i].Code < currencies[j].Code })
+
+	return currencies
+}
+
+// Registry returns the registry attached with WithRegistry, or nil.
+func (client *Client) Registry() *Registry {
+	return client.registry
+}
+
+// marketName returns the name of market used in request paths, preferring the
+// attached registry over the built-in table.
+func (client *Client) marketName(market Market) string {
+	if client.registry != nil {
+		if info, ok := client.registry.Market(market); ok {
+			return info.Name
+		}
+	}
+
+	return market.String()
+}
+
+// lookupPrecision returns the number of decimal places of currency,
+// preferring the attached registry over CurrencyDecimalPlaces, and reports
+// whether it is known.
+func (client *Client) lookupPrecision(currency Currency) (int, bool) {
+	if client.registry != nil {
+		if info, ok := client.registry.Currency(currency); ok {
+			return info.Precision, true
+		}
+	}
+
+	places, ok := CurrencyDecimalPlaces[currency]
+
+	return places, ok
+}
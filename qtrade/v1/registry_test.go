@@ -0,0 +1,219 @@
+package qtrade
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var newListingCommonData = &CommonData{
+	Currencies: []CurrencyData{
+		{Code: "NEW", LongName: "New Coin", Precision: 4, CanWithdraw: true, Status: CurrencyStatusOK},
+		{Code: BTC, LongName: "Bitcoin", Precision: 8, CanWithdraw: true, Status: CurrencyStatusOK},
+	},
+	Markets: []MarketData{
+		{
+			ID:             Market(999),
+			MarketCurrency: "NEW",
+			BaseCurrency:   BTC,
+			MakerFee:       MustParseDecimal("0"),
+			TakerFee:       MustParseDecimal("0.0025"),
+			CanTrade:       true,
+			CanView:        true,
+			CanCancel:      true,
+		},
+	},
+}
+
+func TestRegistry_Load(t *testing.T) {
+	registry := NewRegistry()
+	assert.True(t, registry.UpdatedAt().IsZero())
+
+	registry.Load(newListingCommonData)
+
+	want := MarketInfo{
+		ID:              Market(999),
+		Name:            "NEW_BTC",
+		MarketCurrency:  "NEW",
+		BaseCurrency:    BTC,
+		TakerFee:        MustParseDecimal("0.0025"),
+		MarketPrecision: 4,
+		BasePrecision:   8,
+		CanTrade:        true,
+		CanCancel:       true,
+		CanView:         true,
+	}
+
+	got, ok := registry.Market(999)
+	if assert.True(t, ok) {
+		assert.Equal(t, want, got)
+	}
+
+	got, ok = registry.MarketByName("new_btc")
+	if assert.True(t, ok) {
+		assert.Equal(t, want, got)
+	}
+
+	_, ok = registry.Market(LTC_BTC)
+	assert.False(t, ok)
+
+	for _, s := range []string{"new_btc", "999"} {
+		market, err := registry.ParseMarket(s)
+		if assert.NoError(t, err, s) {
+			assert.Equal(t, Market(999), market)
+		}
+	}
+
+	// markets known to the package are accepted even if not listed
+	market, err := registry.ParseMarket("LTC_BTC")
+	if assert.NoError(t, err) {
+		assert.Equal(t, LTC_BTC, market)
+	}
+
+	_, err = registry.ParseMarket("998")
+	assert.Error(t, err)
+
+	_, ok = registry.MarketByName("LTC_BTC")
+	assert.False(t, ok)
+
+	currency, ok := registry.Currency("NEW")
+	if assert.True(t, ok) {
+		assert.Equal(t, 4, currency.Precision)
+		assert.Equal(t, "New Coin", currency.LongName)
+	}
+
+	assert.Len(t, registry.Markets(), 1)
+
+	currencies := registry.Currencies()
+	if assert.Len(t, currencies, 2) {
+		assert.Equal(t, BTC, currencies[0].Code)
+	}
+
+	assert.False(t, registry.UpdatedAt().IsZero())
+
+	// currencies that are neither listed nor built in have no known precision
+	registry.Load(&CommonData{
+		Markets: []MarketData{{ID: Market(997), MarketCurrency: "ODD", BaseCurrency: BTC, CanTrade: true}},
+	})
+
+	got, ok = registry.Market(997)
+	if assert.True(t, ok) {
+		assert.Equal(t, UnknownPrecision, got.MarketPrecision)
+		assert.Equal(t, 8, got.BasePrecision)
+	}
+}
+
+func TestRegistry_Refresh(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/common",
+		httpmock.NewStringResponder(200, commonTestData))
+
+	client := NewPublicClient(Configuration{Endpoint: "http://localhost"})
+
+	registry, err := LoadRegistry(context.Background(), client)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	market, ok := registry.MarketByName("MMO_BTC")
+	if assert.True(t, ok) {
+		assert.Equal(t, MMO_BTC, market.ID)
+		assert.Equal(t, 8, market.MarketPrecision)
+		assert.False(t, market.CanTrade)
+	}
+
+	// a failed refresh keeps the previous contents
+	httpmock.RegisterResponder("GET", "http://localhost/v1/common",
+		httpmock.NewStringResponder(500, ""))
+
+	client.RetryPolicy = nil
+
+	assert.Error(t, registry.Refresh(context.Background(), client))
+
+	_, ok = registry.Market(MMO_BTC)
+	assert.True(t, ok)
+}
+
+func TestRegistry_Run(t *testing.T) {
+	var calls int32
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/common",
+		func(req *http.Request) (*http.Response, error) {
+			if atomic.AddInt32(&calls, 1) == 2 {
+				return httpmock.NewStringResponse(500, ""), nil
+			}
+
+			return httpmock.NewStringResponse(200, commonTestData), nil
+		})
+
+	client := NewPublicClient(Configuration{Endpoint: "http://localhost"}, WithRetryPolicy(nil))
+
+	errs := make(chan error, 10)
+	registry := NewRegistry()
+	registry.OnError = func(err error) { errs <- err }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+
+	go func() { done <- registry.Run(ctx, client, time.Millisecond*10) }()
+
+	select {
+	case err := <-errs:
+		assert.True(t, IsRetryable(err))
+	case <-time.After(time.Second):
+		t.Fatal("refresh error was not reported")
+	}
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+
+	_, ok := registry.Market(MMO_BTC)
+	assert.True(t, ok)
+	assert.GreaterOrEqual(t, int(atomic.LoadInt32(&calls)), 2)
+}
+
+func TestClient_WithRegistry(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	registry := NewRegistry()
+	registry.Load(newListingCommonData)
+
+	client, err := NewClient(testConfig, WithRegistry(registry))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, registry, client.Registry())
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/ticker/NEW_BTC",
+		httpmock.NewStringResponder(200, `{"data": {"id": 999, "id_hr": "NEW_BTC", "last": "0.00001234"}}`))
+
+	ticker, err := client.GetTicker(context.Background(), Market(999))
+	if assert.NoError(t, err) {
+		assert.Equal(t, MustParseDecimal("0.00001234"), ticker.Last)
+	}
+
+	httpmock.RegisterResponder("POST", "http://localhost/v1/user/buy_limit",
+		func(req *http.Request) (*http.Response, error) {
+			body, _ := ioutil.ReadAll(req.Body)
+			assert.JSONEq(t, `{"amount":"12.3456","market_id":999,"price":"0.00001234"}`, string(body))
+
+			return httpmock.NewStringResponse(200, buyLimitData), nil
+		})
+
+	_, err = client.CreateBuyLimit(context.Background(),
+		MustParseDecimal("12.34567"), Market(999), MustParseDecimal("0.00001234"))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,111 @@
+package qtrade
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// ReplaceResult describes the steps taken by ReplaceOrder.
+type ReplaceResult struct {
+	// Original is the original order, as read after its cancellation, or
+	// before it if ReplaceOrder stopped earlier. It is nil if the order could
+	// not be read.
+	Original *Order
+	// Canceled reports whether the original order was cancelled by
+	// ReplaceOrder. It is false if the order had closed on its own first.
+	Canceled bool
+	// Filled is the amount of the original order that was filled.
+	Filled Decimal
+	// Amount is the amount of the replacement: the new total amount less
+	// Filled. The replacement is not placed if it is not positive.
+	Amount Decimal
+	// Replacement is the new order, or nil if none was placed.
+	Replacement *Order
+}
+
+// ReplaceOrder moves the open order id to newPrice, and resizes it so that
+// newAmount is traded in total. qTrade cannot amend orders, so the order is
+// cancelled, read again to learn how much of it filled meanwhile, and
+// replaced by an order for what is left. A zero newPrice or newAmount keeps
+// the price or amount of the original order.
+//
+// The replacement is checked with PrepareOrder before the order is
+// cancelled. The result describes the steps that were taken even if one of
+// them fails, so that a cancelled order whose replacement failed is not lost.
+func (client *Client) ReplaceOrder(ctx context.Context, id int, newPrice, newAmount Decimal) (*ReplaceResult, error) {
+	result := new(ReplaceResult)
+
+	if newPrice.Sign() < 0 || newAmount.Sign() < 0 {
+		return result, errors.Wrapf(ErrInvalidOrder, "cannot replace order %v with price %v and amount %v", id, newPrice, newAmount)
+	}
+
+	order, err := client.GetOrder(ctx, id)
+	if err != nil {
+		return result, errors.Wrap(err, fmt.Sprintf("failed to replace order %v", id))
+	}
+
+	result.Original = order
+
+	if !order.Open {
+		return result, errors.Wrap(ErrOrderClosed, fmt.Sprintf("failed to replace order %v", id))
+	}
+
+	if newPrice.IsZero() {
+		newPrice = order.Price
+	}
+
+	if newAmount.IsZero() {
+		newAmount = order.MarketAmount
+	}
+
+	if amount := newAmount.Sub(filledAmount(order)); amount.Sign() > 0 {
+		request := OrderRequest{Market: order.Market, Side: order.OrderType, Amount: amount, Price: newPrice}
+		if _, err := client.PrepareOrder(request); err != nil {
+			return result, errors.Wrap(err, fmt.Sprintf("failed to replace order %v", id))
+		}
+	}
+
+	err = client.CancelOrder(ctx, id)
+	if err != nil && !IsOrderClosed(err) {
+		return result, errors.Wrap(err, fmt.Sprintf("failed to replace order %v", id))
+	}
+
+	result.Canceled = err == nil
+
+	order, err = client.GetOrder(ctx, id)
+	if err != nil {
+		return result, errors.Wrap(err, fmt.Sprintf("order %v was cancelled but could not be read", id))
+	}
+
+	result.Original = order
+
+	if order.Open {
+		return result, errors.Errorf("order %v is still open after its cancellation", id)
+	}
+
+	result.Filled = filledAmount(order)
+	result.Amount = newAmount.Sub(result.Filled)
+
+	if result.Amount.Sign() <= 0 {
+		return result, nil
+	}
+
+	result.Replacement, err = client.PlaceOrder(ctx, OrderRequest{
+		Market: order.Market,
+		Side:   order.OrderType,
+		Amount: result.Amount,
+		Price:  newPrice,
+	})
+	if err != nil {
+		return result, errors.Wrap(err, fmt.Sprintf("order %v was cancelled but could not be replaced", id))
+	}
+
+	return result, nil
+}
+
+// filledAmount returns the amount of order that was traded.
+func filledAmount(order *Order) Decimal {
+	return order.MarketAmount.Sub(order.MarketAmountRemaining)
+}
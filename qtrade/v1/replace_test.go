@@ -0,0 +1,168 @@
+package qtrade
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// orderData encodes order as a GetOrder response.
+func orderData(t *testing.T, order Order) string {
+	result := new(GetOrderResult)
+	result.Data.Order = order
+
+	data, err := json.Marshal(result)
+	assert.NoError(t, err)
+
+	return string(data)
+}
+
+func TestClient_ReplaceOrder(t *testing.T) {
+	open := Order{
+		ID:                    5,
+		Market:                LTC_BTC,
+		OrderType:             BuyLimit,
+		MarketAmount:          MustParseDecimal("10"),
+		MarketAmountRemaining: MustParseDecimal("8"),
+		Price:                 MustParseDecimal("0.01"),
+		Open:                  true,
+	}
+
+	closed := func(remaining string) Order {
+		order := open
+		order.MarketAmountRemaining = MustParseDecimal(remaining)
+		order.Open = false
+		order.CloseReason = "canceled"
+
+		return order
+	}
+
+	testCases := []struct {
+		name            string
+		reads           []Order
+		cancelStatus    int
+		price           Decimal
+		amount          Decimal
+		wantErr         string
+		wantCanceled    bool
+		wantFilled      Decimal
+		wantAmount      Decimal
+		wantReplacement string
+		wantCancels     int
+	}{
+		{
+			name:            "partially filled",
+			reads:           []Order{open, closed("6")},
+			price:           MustParseDecimal("0.02"),
+			wantCanceled:    true,
+			wantFilled:      MustParseDecimal("4"),
+			wantAmount:      MustParseDecimal("6"),
+			wantReplacement: `{"amount":"6.00000000","market_id":1,"price":"0.02000000"}`,
+			wantCancels:     1,
+		},
+		{
+			name:            "resized",
+			reads:           []Order{open, closed("8")},
+			amount:          MustParseDecimal("5"),
+			wantCanceled:    true,
+			wantFilled:      MustParseDecimal("2"),
+			wantAmount:      MustParseDecimal("3"),
+			wantReplacement: `{"amount":"3.00000000","market_id":1,"price":"0.01000000"}`,
+			wantCancels:     1,
+		},
+		{
+			name:         "filled before cancellation",
+			reads:        []Order{open, closed("0")},
+			cancelStatus: 400,
+			price:        MustParseDecimal("0.02"),
+			wantFilled:   MustParseDecimal("10"),
+			wantAmount:   MustParseDecimal("0"),
+			wantCancels:  1,
+		},
+		{
+			name:         "replacement failed",
+			reads:        []Order{open, closed("8")},
+			price:        MustParseDecimal("0.5"),
+			wantErr:      "order 5 was cancelled but could not be replaced: failed to create buy order for LTC_BTC: API response: 400: Insufficient funds",
+			wantCanceled: true,
+			wantFilled:   MustParseDecimal("2"),
+			wantAmount:   MustParseDecimal("8"),
+			wantCancels:  1,
+		},
+		{
+			name:    "invalid price",
+			reads:   []Order{open},
+			price:   MustParseDecimal("0.000000001"),
+			wantErr: "failed to replace order 5: price 0.000000001 for LTC_BTC is below the precision of 8 decimal places: invalid order",
+		},
+		{
+			name:    "already closed",
+			reads:   []Order{closed("0")},
+			price:   MustParseDecimal("0.02"),
+			wantErr: "failed to replace order 5: order already closed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			registerTradableMarket()
+
+			reads := make([]string, len(tc.reads))
+			for i, order := range tc.reads {
+				reads[i] = orderData(t, order)
+			}
+
+			httpmock.RegisterResponder("GET", "http://localhost/v1/user/order/5", sequenceResponder(reads...))
+
+			if tc.cancelStatus != 0 {
+				httpmock.RegisterResponder("POST", "http://localhost/v1/user/cancel_order", httpmock.NewStringResponder(tc.cancelStatus,
+					`{"errors": [{"code": "order_closed", "title": "Order already closed"}]}`))
+			} else {
+				httpmock.RegisterResponder("POST", "http://localhost/v1/user/cancel_order", httpmock.NewStringResponder(200, ""))
+			}
+
+			var body string
+
+			httpmock.RegisterResponder("POST", "http://localhost/v1/user/buy_limit",
+				func(req *http.Request) (*http.Response, error) {
+					b, _ := ioutil.ReadAll(req.Body)
+					body = string(b)
+
+					if tc.price.String() == "0.5" {
+						return httpmock.NewStringResponse(400, `{"errors": [{"code": "insuff_funds", "title": "Insufficient funds"}]}`), nil
+					}
+
+					return httpmock.NewStringResponse(200, buyLimitData), nil
+				})
+
+			result, err := testClient.ReplaceOrder(context.Background(), 5, tc.price, tc.amount)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.wantCanceled, result.Canceled)
+			assert.Equal(t, tc.wantFilled, result.Filled)
+			assert.Equal(t, tc.wantAmount, result.Amount)
+			assert.Equal(t, tc.wantCancels, httpmock.GetCallCountInfo()["POST http://localhost/v1/user/cancel_order"])
+
+			if tc.wantReplacement != "" {
+				assert.JSONEq(t, tc.wantReplacement, body)
+				if assert.NotNil(t, result.Replacement) {
+					assert.Equal(t, 13254, result.Replacement.ID)
+				}
+			} else {
+				assert.Nil(t, result.Replacement)
+			}
+		})
+	}
+}
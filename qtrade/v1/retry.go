@@ -0,0 +1,135 @@
+package qtrade
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RequestInfo describes the API call a RetryPolicy is deciding about.
+type RequestInfo struct {
+	Method string
+	Path   string
+	// Idempotent is false for calls that may have side effects when repeated,
+	// such as placing an order or requesting a withdrawal.
+	Idempotent bool
+}
+
+// RetryPolicy decides whether a failed request is sent again.
+type RetryPolicy interface {
+	// Retry is called after every failed attempt, with the number of retries
+	// made so far. It returns how long to wait before the next attempt, and
+	// whether there should be one at all.
+	Retry(req RequestInfo, retries int, err error) (time.Duration, bool)
+}
+
+// BackoffPolicy is the default RetryPolicy. It retries with exponential
+// backoff and jitter, and does not retry calls that are not idempotent unless
+// the API rejected them before acting on them.
+type BackoffPolicy struct {
+	MaxRetries int
+	// BaseDelay is the delay before the first retry. It doubles for every
+	// following retry, up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Jitter is the fraction of each delay, from 0 to 1, that is randomised to
+	// keep concurrent clients from retrying in lockstep.
+	Jitter float64
+	// RetryUnsafe also retries calls that are not idempotent. Retrying an order
+	// after a server error can place it twice.
+	RetryUnsafe bool
+	// Retryable decides whether an error is worth retrying. It defaults to IsRetryable.
+	Retryable func(err error) bool
+}
+
+// NewBackoffPolicy returns a BackoffPolicy with sensible defaults for the
+// maximum delay and jitter.
+func NewBackoffPolicy(maxRetries int, baseDelay time.Duration) *BackoffPolicy {
+	return &BackoffPolicy{
+		MaxRetries: maxRetries,
+		BaseDelay:  baseDelay,
+		MaxDelay:   time.Second * 30,
+		Jitter:     0.5,
+	}
+}
+
+func (policy *BackoffPolicy) Retry(req RequestInfo, retries int, err error) (time.Duration, bool) {
+	if retries >= policy.MaxRetries {
+		return 0, false
+	}
+
+	retryable := policy.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+
+	if !retryable(err) {
+		return 0, false
+	}
+
+	// a rate limited request never reached the exchange, and the client's
+	// rate limiter already holds the next attempt until the window resets
+	if errors.Is(err, ErrTooManyRequests) {
+		return 0, true
+	}
+
+	if !req.Idempotent && !policy.RetryUnsafe {
+		return 0, false
+	}
+
+	return policy.delay(retries), true
+}
+
+func (policy *BackoffPolicy) delay(retries int) time.Duration {
+	delay := policy.BaseDelay
+	for i := 0; i < retries && (policy.MaxDelay <= 0 || delay < policy.MaxDelay); i++ {
+		delay *= 2
+	}
+
+	if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+
+	jitter := time.Duration(float64(delay) * policy.Jitter)
+	if jitter > 0 {
+		delay -= time.Duration(rand.Int63n(int64(jitter) + 1))
+	}
+
+	return delay
+}
+
+// IsRetryable reports whether err is a rate limit, a transient server error or
+// a network timeout, any of which may succeed when tried again.
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, ErrTooManyRequests) || errors.Is(err, ErrHTTPRetryable) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// sleepContext waits for d, or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
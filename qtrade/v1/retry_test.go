@@ -0,0 +1,181 @@
+package qtrade
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoffPolicy_Retry(t *testing.T) {
+	serverErr := &APIError{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}
+	rateLimitErr := &APIError{StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests"}
+	badRequestErr := &APIError{StatusCode: http.StatusBadRequest, Status: "400 Bad Request"}
+
+	get := RequestInfo{Method: "GET", Path: "/v1/user/me", Idempotent: true}
+	order := RequestInfo{Method: "POST", Path: "/v1/user/buy_limit"}
+
+	testCases := []struct {
+		name      string
+		policy    *BackoffPolicy
+		req       RequestInfo
+		retries   int
+		err       error
+		wantRetry bool
+	}{
+		{
+			name:      "server error on idempotent call",
+			policy:    NewBackoffPolicy(2, time.Millisecond),
+			req:       get,
+			err:       serverErr,
+			wantRetry: true,
+		},
+		{
+			name:      "retries exhausted",
+			policy:    NewBackoffPolicy(2, time.Millisecond),
+			req:       get,
+			retries:   2,
+			err:       serverErr,
+			wantRetry: false,
+		},
+		{
+			name:      "client error is not retryable",
+			policy:    NewBackoffPolicy(2, time.Millisecond),
+			req:       get,
+			err:       badRequestErr,
+			wantRetry: false,
+		},
+		{
+			name:      "server error on order is not retried",
+			policy:    NewBackoffPolicy(2, time.Millisecond),
+			req:       order,
+			err:       serverErr,
+			wantRetry: false,
+		},
+		{
+			name:      "rate limited order is retried",
+			policy:    NewBackoffPolicy(2, time.Millisecond),
+			req:       order,
+			err:       rateLimitErr,
+			wantRetry: true,
+		},
+		{
+			name:      "server error on order with unsafe retries",
+			policy:    &BackoffPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, RetryUnsafe: true},
+			req:       order,
+			err:       serverErr,
+			wantRetry: true,
+		},
+		{
+			name:      "cancelled context is not retryable",
+			policy:    NewBackoffPolicy(2, time.Millisecond),
+			req:       get,
+			err:       errors.Wrap(context.Canceled, "could not complete HTTP request"),
+			wantRetry: false,
+		},
+		{
+			name: "custom retryable hook",
+			policy: &BackoffPolicy{
+				MaxRetries: 2,
+				BaseDelay:  time.Millisecond,
+				Retryable: func(err error) bool {
+					var apiErr *APIError
+					return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest
+				},
+			},
+			req:       get,
+			err:       badRequestErr,
+			wantRetry: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, gotRetry := tc.policy.Retry(tc.req, tc.retries, tc.err)
+
+			assert.Equal(t, tc.wantRetry, gotRetry)
+		})
+	}
+}
+
+func TestBackoffPolicy_delay(t *testing.T) {
+	policy := &BackoffPolicy{
+		MaxRetries: 10,
+		BaseDelay:  time.Millisecond * 100,
+		MaxDelay:   time.Second,
+		Jitter:     0.5,
+	}
+
+	testCases := []struct {
+		retries int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{retries: 0, min: time.Millisecond * 50, max: time.Millisecond * 100},
+		{retries: 1, min: time.Millisecond * 100, max: time.Millisecond * 200},
+		{retries: 2, min: time.Millisecond * 200, max: time.Millisecond * 400},
+		{retries: 8, min: time.Millisecond * 500, max: time.Second},
+	}
+
+	for _, tc := range testCases {
+		for i := 0; i < 20; i++ {
+			got := policy.delay(tc.retries)
+
+			assert.GreaterOrEqual(t, int64(got), int64(tc.min))
+			assert.LessOrEqual(t, int64(got), int64(tc.max))
+		}
+	}
+}
+
+func TestClient_OrderNotRetried(t *testing.T) {
+	retryClient, _ := NewClient(
+		Configuration{
+			HMACKeypair: "1:1111111111111111111111111111111111111111111111111111111111111111",
+			Endpoint:    "http://localhost",
+			Timeout:     time.Second * 10,
+			Backoff:     time.Millisecond,
+			MaxRetries:  3,
+		})
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	registerTradableMarket()
+
+	httpmock.RegisterResponder("POST", "http://localhost/v1/user/buy_limit",
+		httpmock.NewStringResponder(500, ``))
+
+	_, err := retryClient.CreateBuyLimit(context.Background(), DecimalFromInt(10), LTC_BTC, MustParseDecimal("0.1"))
+	assert.ErrorIs(t, err, ErrHTTPRetryable)
+
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["POST http://localhost/v1/user/buy_limit"])
+}
+
+func TestClient_RetryBackoffContext(t *testing.T) {
+	retryClient, _ := NewClient(
+		Configuration{
+			HMACKeypair: "1:1111111111111111111111111111111111111111111111111111111111111111",
+			Endpoint:    "http://localhost",
+			Timeout:     time.Second * 10,
+			Backoff:     time.Minute,
+			MaxRetries:  3,
+		})
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/user/me",
+		httpmock.NewStringResponder(503, ``))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+
+	_, err := retryClient.GetUserInfo(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET http://localhost/v1/user/me"])
+}
@@ -0,0 +1,341 @@
+package qtrade
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// OrderEventKind is the kind of an OrderEvent.
+type OrderEventKind int
+
+// The kinds of OrderEvent.
+const (
+	// OrderPartiallyFilled is published when an open order has new trades.
+	OrderPartiallyFilled OrderEventKind = iota + 1
+	// OrderFilled is published when an order closes with nothing remaining.
+	OrderFilled
+	// OrderCanceled is published when an order closes before it was filled.
+	// Order.CloseReason says why.
+	OrderCanceled
+)
+
+func (kind OrderEventKind) String() string {
+	switch kind {
+	case OrderPartiallyFilled:
+		return "partially filled"
+	case OrderFilled:
+		return "filled"
+	case OrderCanceled:
+		return "canceled"
+	}
+
+	return "unknown"
+}
+
+// OrderEvent is published by an OrderTracker when a tracked order trades or
+// closes.
+type OrderEvent struct {
+	Kind OrderEventKind
+	// Order is the snapshot of the order that the event was found in.
+	Order *Order
+	// Trades holds the trades of the order since the previous event, oldest
+	// first. It may be empty for OrderFilled and OrderCanceled.
+	Trades []PrivateTrade
+}
+
+// trackedOrder holds what an OrderTracker knows of an order.
+type trackedOrder struct {
+	trades map[int]bool
+}
+
+// OrderTracker follows a set of orders by polling GetOrder, and publishes
+// their fills and closures. Closed orders are no longer tracked.
+//
+// Polls are adaptive: they are made every MinInterval after a change, and
+// then twice as far apart each time nothing changed, up to MaxInterval.
+//
+// An OrderTracker is safe for concurrent use.
+type OrderTracker struct {
+	// MinInterval and MaxInterval bound the time between polls. They may be
+	// changed before Run is called.
+	MinInterval time.Duration
+	MaxInterval time.Duration
+
+	// OnError, if set, is called with the errors of polls made by Run.
+	OnError func(error)
+
+	api    PrivateAPI
+	events chan OrderEvent
+
+	// polling serializes polls, so that each order's trades are compared
+	// with those published before.
+	polling sync.Mutex
+
+	mu     sync.Mutex
+	orders map[int]*trackedOrder
+}
+
+// NewOrderTracker returns a tracker of the orders with the given IDs, which
+// polls between every second and every 30 seconds by default.
+func NewOrderTracker(api PrivateAPI, ids ...int) *OrderTracker {
+	tracker := &OrderTracker{
+		MinInterval: time.Second,
+		MaxInterval: time.Second * 30,
+		api:         api,
+		events:      make(chan OrderEvent, 16),
+		orders:      map[int]*trackedOrder{},
+	}
+
+	tracker.Add(ids...)
+
+	return tracker
+}
+
+// Events returns the channel on which the tracker publishes events. Polls
+// wait for the channel to be read when its buffer is full, so that no event
+// is lost. The channel is not closed when Run returns.
+func (tracker *OrderTracker) Events() <-chan OrderEvent {
+	return tracker.events
+}
+
+// Add starts tracking the orders with the given IDs. The trades an order
+// already has are published with the first poll that reads it.
+func (tracker *OrderTracker) Add(ids ...int) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	for _, id := range ids {
+		if _, ok := tracker.orders[id]; !ok {
+			tracker.orders[id] = &trackedOrder{trades: map[int]bool{}}
+		}
+	}
+}
+
+// Remove stops tracking the order with the given ID.
+func (tracker *OrderTracker) Remove(id int) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	delete(tracker.orders, id)
+}
+
+// Tracked returns the IDs of the orders being tracked, in ascending order.
+func (tracker *OrderTracker) Tracked() []int {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	ids := make([]int, 0, len(tracker.orders))
+	for id := range tracker.orders {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	return ids
+}
+
+// Run polls the tracked orders immediately and then at adaptive intervals
+// until ctx is done, and returns the context's error. It keeps running when no
+// order is left, so that more may be added. Failed polls are reported to
+// OnError.
+func (tracker *OrderTracker) Run(ctx context.Context) error {
+	var interval time.Duration
+
+	for {
+		changed, err := tracker.poll(ctx)
+		if err != nil && tracker.OnError != nil && ctx.Err() == nil {
+			tracker.OnError(err)
+		}
+
+		interval = nextInterval(interval, tracker.MinInterval, tracker.MaxInterval, changed)
+
+		timer := time.NewTimer(interval)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Poll reads every tracked order once, and publishes their changes. If some
+// orders could not be read, the others are still polled, and the first error
+// is returned.
+func (tracker *OrderTracker) Poll(ctx context.Context) error {
+	_, err := tracker.poll(ctx)
+
+	return err
+}
+
+// poll is Poll, and also reports whether any event was published.
+func (tracker *OrderTracker) poll(ctx context.Context) (bool, error) {
+	tracker.polling.Lock()
+	defer tracker.polling.Unlock()
+
+	var (
+		changed  bool
+		firstErr error
+	)
+
+	for _, id := range tracker.Tracked() {
+		order, err := tracker.api.GetOrder(ctx, id)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+
+			if ctx.Err() != nil {
+				break
+			}
+
+			continue
+		}
+
+		event, ok := tracker.update(id, order)
+		if !ok {
+			continue
+		}
+
+		changed = true
+
+		select {
+		case tracker.events <- event:
+		case <-ctx.Done():
+			return changed, ctx.Err()
+		}
+	}
+
+	return changed, firstErr
+}
+
+// update records the new trades of order, and returns the event they make,
+// if any. Closed orders are no longer tracked.
+func (tracker *OrderTracker) update(id int, order *Order) (OrderEvent, bool) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracked, ok := tracker.orders[id]
+	if !ok {
+		// removed while it was being read
+		return OrderEvent{}, false
+	}
+
+	event := OrderEvent{Kind: OrderPartiallyFilled, Order: order}
+
+	for _, trade := range order.Trades {
+		if !tracked.trades[trade.ID] {
+			tracked.trades[trade.ID] = true
+			event.Trades = append(event.Trades, trade)
+		}
+	}
+
+	sort.Slice(event.Trades, func(i, j int) bool { return event.Trades[i].ID < event.Trades[j].ID })
+
+	if order.Open {
+		return event, len(event.Trades) > 0
+	}
+
+	delete(tracker.orders, id)
+
+	event.Kind = OrderCanceled
+	if order.MarketAmountRemaining.IsZero() {
+		event.Kind = OrderFilled
+	}
+
+	return event, true
+}
+
+// nextInterval returns the time to wait before the next poll: minInterval
+// after a poll that found changes, and otherwise twice the previous interval,
+// up to maxInterval unless that is below minInterval.
+func nextInterval(interval, minInterval, maxInterval time.Duration, changed bool) time.Duration {
+	if changed || interval < minInterval {
+		return minInterval
+	}
+
+	interval *= 2
+	if interval > maxInterval {
+		interval = maxInterval
+	}
+
+	if interval < minInterval {
+		interval = minInterval
+	}
+
+	return interval
+}
+
+// WaitOptions sets how WaitForOrder polls an order.
+type WaitOptions struct {
+	// MinInterval and MaxInterval bound the time between polls, as for an
+	// OrderTracker. They default to a second and 30 seconds.
+	MinInterval time.Duration
+	MaxInterval time.Duration
+	// OnEvent, if set, is called with every event of the order, including
+	// partial fills, before WaitForOrder returns.
+	OnEvent func(OrderEvent)
+}
+
+// WaitForOrder waits for the order id to close, using the WaitForOrder
+// function.
+func (client *Client) WaitForOrder(ctx context.Context, id int, opts WaitOptions) (*Order, error) {
+	return WaitForOrder(ctx, client, id, opts)
+}
+
+// WaitForOrder polls the order id of api until it is filled or cancelled, and
+// returns its final state. It returns early with the error of a failed poll,
+// or the context's error when ctx is done.
+func WaitForOrder(ctx context.Context, api PrivateAPI, id int, opts WaitOptions) (*Order, error) {
+	tracker := NewOrderTracker(api, id)
+
+	if opts.MinInterval > 0 {
+		tracker.MinInterval = opts.MinInterval
+	}
+
+	if opts.MaxInterval > 0 {
+		tracker.MaxInterval = opts.MaxInterval
+	}
+
+	var interval time.Duration
+
+	for {
+		changed, err := tracker.poll(ctx)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		for pending := changed; pending; {
+			select {
+			case event := <-tracker.events:
+				if opts.OnEvent != nil {
+					opts.OnEvent(event)
+				}
+
+				if event.Kind != OrderPartiallyFilled {
+					return event.Order, nil
+				}
+			default:
+				pending = false
+			}
+		}
+
+		interval = nextInterval(interval, tracker.MinInterval, tracker.MaxInterval, changed)
+
+		timer := time.NewTimer(interval)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
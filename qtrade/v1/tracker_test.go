@@ -0,0 +1,191 @@
+package qtrade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// trackedOrderData returns a GetOrder response for an order buying 10, with
+// the given trades of 2 each.
+func trackedOrderData(t *testing.T, open bool, tradeIDs ...int) string {
+	order := Order{
+		ID:                    5,
+		Market:                LTC_BTC,
+		OrderType:             BuyLimit,
+		MarketAmount:          MustParseDecimal("10"),
+		MarketAmountRemaining: MustParseDecimal("10"),
+		Price:                 MustParseDecimal("0.01"),
+		Open:                  open,
+	}
+
+	for _, id := range tradeIDs {
+		order.Trades = append(order.Trades, PrivateTrade{ID: id, MarketAmount: MustParseDecimal("2")})
+		order.MarketAmountRemaining = order.MarketAmountRemaining.Sub(MustParseDecimal("2"))
+	}
+
+	if !open && !order.MarketAmountRemaining.IsZero() {
+		order.CloseReason = "canceled"
+	}
+
+	return orderData(t, order)
+}
+
+func eventTradeIDs(event OrderEvent) []int {
+	ids := make([]int, len(event.Trades))
+	for i, trade := range event.Trades {
+		ids[i] = trade.ID
+	}
+
+	return ids
+}
+
+func TestOrderTracker_Poll(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/user/order/5", sequenceResponder(
+		trackedOrderData(t, true),
+		trackedOrderData(t, true, 2, 1),
+		trackedOrderData(t, true, 1, 2),
+		trackedOrderData(t, false, 1, 2, 3, 4, 5),
+	))
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/user/order/6", sequenceResponder(
+		trackedOrderData(t, true, 7),
+		trackedOrderData(t, false, 7),
+	))
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/user/order/7",
+		httpmock.NewStringResponder(404, `{"errors": [{"code": "order_not_found", "title": "Order not found"}]}`))
+
+	tracker := NewOrderTracker(testClient, 5, 6, 7)
+	assert.Equal(t, []int{5, 6, 7}, tracker.Tracked())
+
+	type polled struct {
+		kind   OrderEventKind
+		trades []int
+	}
+
+	poll := func(wantNotFound bool) []polled {
+		err := tracker.Poll(context.Background())
+		if wantNotFound {
+			assert.True(t, IsOrderNotFound(err))
+		} else {
+			assert.NoError(t, err)
+		}
+
+		var events []polled
+
+		for {
+			select {
+			case event := <-tracker.Events():
+				events = append(events, polled{event.Kind, eventTradeIDs(event)})
+			default:
+				return events
+			}
+		}
+	}
+
+	assert.Equal(t, []polled{{OrderPartiallyFilled, []int{7}}}, poll(true))
+	assert.Equal(t, []polled{{OrderPartiallyFilled, []int{1, 2}}, {OrderCanceled, []int{}}}, poll(true))
+	assert.Equal(t, []int{5, 7}, tracker.Tracked())
+	assert.Nil(t, poll(true))
+
+	tracker.Remove(7)
+	assert.Equal(t, []polled{{OrderFilled, []int{3, 4, 5}}}, poll(false))
+	assert.Empty(t, tracker.Tracked())
+
+	assert.Equal(t, 4, httpmock.GetCallCountInfo()["GET http://localhost/v1/user/order/5"])
+	assert.Equal(t, 2, httpmock.GetCallCountInfo()["GET http://localhost/v1/user/order/6"])
+}
+
+func TestOrderTracker_Run(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/user/order/5", sequenceResponder(
+		trackedOrderData(t, true, 1),
+		trackedOrderData(t, false, 1, 2, 3, 4, 5),
+	))
+
+	tracker := NewOrderTracker(testClient, 5)
+	tracker.MinInterval = time.Millisecond
+	tracker.MaxInterval = time.Millisecond * 4
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error)
+
+	go func() {
+		done <- tracker.Run(ctx)
+	}()
+
+	assert.Equal(t, OrderPartiallyFilled, (<-tracker.Events()).Kind)
+
+	event := <-tracker.Events()
+	assert.Equal(t, OrderFilled, event.Kind)
+	assert.Equal(t, []int{2, 3, 4, 5}, eventTradeIDs(event))
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+}
+
+func TestNextInterval(t *testing.T) {
+	testCases := []struct {
+		name     string
+		interval time.Duration
+		changed  bool
+		want     time.Duration
+	}{
+		{name: "first poll", interval: 0, want: time.Second},
+		{name: "unchanged", interval: time.Second, want: time.Second * 2},
+		{name: "capped", interval: time.Second * 20, want: time.Second * 30},
+		{name: "changed", interval: time.Second * 30, changed: true, want: time.Second},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, nextInterval(tc.interval, time.Second, time.Second*30, tc.changed))
+		})
+	}
+}
+
+func TestClient_WaitForOrder(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/user/order/5", sequenceResponder(
+		trackedOrderData(t, true),
+		trackedOrderData(t, true, 1),
+		trackedOrderData(t, true, 1),
+		trackedOrderData(t, false, 1, 2, 3, 4, 5),
+	))
+
+	var kinds []OrderEventKind
+
+	order, err := testClient.WaitForOrder(context.Background(), 5, WaitOptions{
+		MinInterval: time.Millisecond,
+		MaxInterval: time.Millisecond * 4,
+		OnEvent:     func(event OrderEvent) { kinds = append(kinds, event.Kind) },
+	})
+	if assert.NoError(t, err) {
+		assert.False(t, order.Open)
+		assert.True(t, order.MarketAmountRemaining.IsZero())
+	}
+
+	assert.Equal(t, []OrderEventKind{OrderPartiallyFilled, OrderFilled}, kinds)
+	assert.Equal(t, 4, httpmock.GetTotalCallCount())
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/user/order/6",
+		httpmock.NewStringResponder(200, trackedOrderData(t, true)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+
+	_, err = testClient.WaitForOrder(ctx, 6, WaitOptions{MinInterval: time.Millisecond})
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
@@ -3,7 +3,10 @@
 
 package qtrade
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // base types
 
@@ -33,42 +36,42 @@ type EmailAddress struct {
 
 type Balance struct {
 	Currency Currency `json:"currency"`
-	Balance  string   `json:"balance"`
+	Balance  Decimal  `json:"balance"`
 }
 
 type Order struct {
-	BaseAmount            float64        `json:"base_amount,string"`
+	BaseAmount            Decimal        `json:"base_amount"`
 	CreatedAt             time.Time      `json:"created_at"`
 	ID                    int            `json:"id"`
-	MarketAmount          float64        `json:"market_amount,string"`
-	MarketAmountRemaining float64        `json:"market_amount_remaining,string"`
+	MarketAmount          Decimal        `json:"market_amount"`
+	MarketAmountRemaining Decimal        `json:"market_amount_remaining"`
 	Market                Market         `json:"market_id"`
 	Open                  bool           `json:"open"`
 	OrderType             OrderType      `json:"order_type"`
-	Price                 float64        `json:"price,string"`
+	Price                 Decimal        `json:"price"`
 	Trades                []PrivateTrade `json:"trades"`
 	CloseReason           string         `json:"close_reason,omitempty"`
 }
 
 // PublicTrade does not contain detailed info about a trade, and is returned by public endpoints.
 type PublicTrade struct {
-	Amount      float64   `json:"amount,string"`
+	Amount      Decimal   `json:"amount"`
 	CreatedAt   time.Time `json:"created_at"`
 	ID          int       `json:"id"`
-	Price       float64   `json:"price,string"`
+	Price       Decimal   `json:"price"`
 	SellerTaker *bool     `json:"seller_taker,omitempty"`
 }
 
 // PrivateTrade contains detailed information about a trade, and is usually only available to one of the users involved.
 type PrivateTrade struct {
-	BaseAmount   float64   `json:"base_amount,string"`
-	BaseFee      float64   `json:"base_fee,string"`
+	BaseAmount   Decimal   `json:"base_amount"`
+	BaseFee      Decimal   `json:"base_fee"`
 	CreatedAt    time.Time `json:"created_at"`
 	ID           int       `json:"id"`
 	OrderID      int       `json:"order_id,omitempty"`
 	Market       Market    `json:"market_id,omitempty"`
-	MarketAmount float64   `json:"market_amount,string"`
-	Price        float64   `json:"price,string"`
+	MarketAmount Decimal   `json:"market_amount"`
+	Price        Decimal   `json:"price"`
 	Taker        bool      `json:"taker"`
 	Side         string    `json:"side,omitempty"`
 }
@@ -84,7 +87,7 @@ func (err Error) Error() string {
 }
 
 type Transfer struct {
-	Amount         float64                `json:"amount,string"`
+	Amount         Decimal                `json:"amount"`
 	CreatedAt      time.Time              `json:"created_at"`
 	Currency       Currency               `json:"currency"`
 	ID             int                    `json:"id"`
@@ -95,18 +98,18 @@ type Transfer struct {
 }
 
 type Ticker struct {
-	Ask             float64 `json:"ask,string"`
-	Bid             float64 `json:"bid,string"`
-	DayAvgPrice     float64 `json:"day_avg_price,string"`
-	DayChange       float64 `json:"day_change,string"`
-	DayHigh         float64 `json:"day_high,string"`
-	DayLow          float64 `json:"day_low,string"`
-	DayOpen         float64 `json:"day_open,string"`
-	DayVolumeBase   float64 `json:"day_volume_base,string"`
-	DayVolumeMarket float64 `json:"day_volume_market,string"`
+	Ask             Decimal `json:"ask"`
+	Bid             Decimal `json:"bid"`
+	DayAvgPrice     Decimal `json:"day_avg_price"`
+	DayChange       Decimal `json:"day_change"`
+	DayHigh         Decimal `json:"day_high"`
+	DayLow          Decimal `json:"day_low"`
+	DayOpen         Decimal `json:"day_open"`
+	DayVolumeBase   Decimal `json:"day_volume_base"`
+	DayVolumeMarket Decimal `json:"day_volume_market"`
 	Market          Market  `json:"id"`
 	IDHr            string  `json:"id_hr"`
-	Last            float64 `json:"last,string"`
+	Last            Decimal `json:"last"`
 }
 
 type CurrencyData struct {
@@ -123,12 +126,12 @@ type CurrencyData struct {
 type CurrencyConfig struct {
 	AddressVersion                int     `json:"address_version,omitempty"`
 	DefaultSigner                 int     `json:"default_signer"`
-	Price                         float64 `json:"price"`
+	Price                         Decimal `json:"price"`
 	RequiredConfirmations         int     `json:"required_confirmations"`
 	RequiredGenerateConfirmations int     `json:"required_generate_confirmations,omitempty"`
 	SatoshiPerByte                int     `json:"satoshi_per_byte,omitempty"`
 	WifVersion                    int     `json:"wif_version,omitempty"`
-	WithdrawFee                   float64 `json:"withdraw_fee,string"`
+	WithdrawFee                   Decimal `json:"withdraw_fee"`
 	ExplorerAddressURL            string  `json:"explorerAddressURL,omitempty"`
 	ExplorerTransactionURL        string  `json:"explorerTransactionURL,omitempty"`
 	P2ShAddressVersion            int     `json:"p2sh_address_version,omitempty"`
@@ -149,10 +152,10 @@ type MarketData struct {
 	CanTrade       bool           `json:"can_trade"`
 	CanView        bool           `json:"can_view"`
 	ID             Market         `json:"id"`
-	MakerFee       float64        `json:"maker_fee,string"`
+	MakerFee       Decimal        `json:"maker_fee"`
 	MarketCurrency Currency       `json:"market_currency"`
 	Metadata       MarketMetadata `json:"metadata"`
-	TakerFee       float64        `json:"taker_fee,string"`
+	TakerFee       Decimal        `json:"taker_fee"`
 }
 
 type MarketMetadata struct {
@@ -166,7 +169,12 @@ type MarketNotice struct {
 	Type    string `json:"type"`
 }
 
+// Orderbook is a snapshot of the open orders of a market. Bids and Asks hold
+// the exact levels, sorted best price first. Buy and Sell hold the same
+// levels keyed by float64 price, as returned by earlier versions.
 type Orderbook struct {
+	Bids       OrderbookLevels     `json:"bids"`
+	Asks       OrderbookLevels     `json:"asks"`
 	Buy        map[float64]float64 `json:"buy"`
 	LastChange int                 `json:"last_change"`
 	Sell       map[float64]float64 `json:"sell"`
@@ -244,12 +252,12 @@ type GetOHLCVResult struct {
 }
 
 type OHLCVSlice struct {
-	Close  float64   `json:"close,string"`
-	High   float64   `json:"high,string"`
-	Low    float64   `json:"low,string"`
-	Open   float64   `json:"open,string"`
+	Close  Decimal   `json:"close"`
+	High   Decimal   `json:"high"`
+	Low    Decimal   `json:"low"`
+	Open   Decimal   `json:"open"`
 	Time   time.Time `json:"time"`
-	Volume float64   `json:"volume,string"`
+	Volume Decimal   `json:"volume"`
 }
 
 // Private endpoint results
@@ -275,9 +283,9 @@ type GetUserMarketResult struct {
 }
 
 type UserMarketData struct {
-	BaseBalance   float64 `json:"base_balance,string"`
+	BaseBalance   Decimal `json:"base_balance"`
 	ClosedOrders  []Order `json:"closed_orders"`
-	MarketBalance float64 `json:"market_balance,string"`
+	MarketBalance Decimal `json:"market_balance"`
 	OpenOrders    []Order `json:"open_orders"`
 }
 
@@ -317,7 +325,7 @@ type GetWithdrawDetailsResult struct {
 
 type WithdrawDetails struct {
 	Address         string                 `json:"address"`
-	Amount          string                 `json:"amount"`
+	Amount          Decimal                `json:"amount"`
 	CancelRequested bool                   `json:"cancel_requested"`
 	CreatedAt       time.Time              `json:"created_at"`
 	Currency        Currency               `json:"currency"`
@@ -348,7 +356,7 @@ type GetDepositHistoryResult struct {
 
 type DepositDetails struct {
 	Address     string                 `json:"address"`
-	Amount      string                 `json:"amount"`
+	Amount      Decimal                `json:"amount"`
 	CreatedAt   time.Time              `json:"created_at"`
 	Currency    Currency               `json:"currency"`
 	ID          string                 `json:"id"`
@@ -377,3 +385,43 @@ type CreateOrderResult struct {
 		Order Order `json:"order"`
 	} `json:"data"`
 }
+
+// The API identifies markets by their integer ID, while Market encodes as its
+// name in text, for configs and flags. These methods keep the API types
+// encoding markets as the API does.
+
+func (order Order) MarshalJSON() ([]byte, error) {
+	type plain Order
+
+	return json.Marshal(struct {
+		plain
+		Market int `json:"market_id"`
+	}{plain(order), int(order.Market)})
+}
+
+func (trade PrivateTrade) MarshalJSON() ([]byte, error) {
+	type plain PrivateTrade
+
+	return json.Marshal(struct {
+		plain
+		Market int `json:"market_id,omitempty"`
+	}{plain(trade), int(trade.Market)})
+}
+
+func (ticker Ticker) MarshalJSON() ([]byte, error) {
+	type plain Ticker
+
+	return json.Marshal(struct {
+		plain
+		Market int `json:"id"`
+	}{plain(ticker), int(ticker.Market)})
+}
+
+func (market MarketData) MarshalJSON() ([]byte, error) {
+	type plain MarketData
+
+	return json.Marshal(struct {
+		plain
+		ID int `json:"id"`
+	}{plain(market), int(market.ID)})
+}
@@ -0,0 +1,171 @@
+package qtrade
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// OrderbookDiff holds the levels that changed between two snapshots of an
+// orderbook, best price first. A level with a zero Amount was removed.
+type OrderbookDiff struct {
+	Bids OrderbookLevels `json:"bids"`
+	Asks OrderbookLevels `json:"asks"`
+}
+
+// IsEmpty reports whether no level changed.
+func (diff OrderbookDiff) IsEmpty() bool {
+	return len(diff.Bids) == 0 && len(diff.Asks) == 0
+}
+
+// DiffOrderbooks returns the levels of to that differ from from, which may be
+// nil to return every level of to.
+func DiffOrderbooks(from, to *Orderbook) OrderbookDiff {
+	var fromBids, fromAsks OrderbookLevels
+	if from != nil {
+		fromBids, fromAsks = from.Bids, from.Asks
+	}
+
+	return OrderbookDiff{
+		Bids: diffLevels(fromBids, to.Bids, true),
+		Asks: diffLevels(fromAsks, to.Asks, false),
+	}
+}
+
+func diffLevels(from, to OrderbookLevels, desc bool) OrderbookLevels {
+	amounts := make(map[Decimal]Decimal, len(from))
+	for _, level := range from {
+		amounts[level.Price] = level.Amount
+	}
+
+	var changed []OrderbookLevel
+
+	for _, level := range to {
+		if amount, ok := amounts[level.Price]; !ok || amount != level.Amount {
+			changed = append(changed, level)
+		}
+
+		delete(amounts, level.Price)
+	}
+
+	for price := range amounts {
+		changed = append(changed, OrderbookLevel{Price: price})
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	return mergeLevels(changed, desc)
+}
+
+// OrderbookUpdate is published by an OrderbookWatcher for every snapshot that
+// differs from the previous one.
+type OrderbookUpdate struct {
+	Market Market
+	// Book is the new snapshot. It is shared and must not be modified.
+	Book *Orderbook
+	// Diff holds the levels changed since the previous snapshot, or every
+	// level of the first one.
+	Diff OrderbookDiff
+}
+
+// OrderbookWatcher maintains the orderbook of one market by polling
+// GetOrderbook, and publishes the changes between snapshots.
+//
+// An OrderbookWatcher is safe for concurrent use.
+type OrderbookWatcher struct {
+	// OnError, if set, is called with the errors of polls made by Run.
+	OnError func(error)
+
+	api     PublicAPI
+	market  Market
+	updates chan OrderbookUpdate
+
+	// polling serializes polls, so that each diff is against the book
+	// published before it.
+	polling sync.Mutex
+
+	mu   sync.RWMutex
+	book *Orderbook
+}
+
+// NewOrderbookWatcher returns a watcher of the orderbook of market, which has
+// no book until Run has polled it.
+func NewOrderbookWatcher(api PublicAPI, market Market) *OrderbookWatcher {
+	return &OrderbookWatcher{
+		api:     api,
+		market:  market,
+		updates: make(chan OrderbookUpdate, 16),
+	}
+}
+
+// Updates returns the channel on which Run publishes changes. Run waits for
+// the channel to be read when its buffer is full, so that no change is lost.
+// The channel is not closed when Run returns.
+func (watcher *OrderbookWatcher) Updates() <-chan OrderbookUpdate {
+	return watcher.updates
+}
+
+// Book returns the latest snapshot, or nil if there is none yet. It is shared
+// and must not be modified.
+func (watcher *OrderbookWatcher) Book() *Orderbook {
+	watcher.mu.RLock()
+	defer watcher.mu.RUnlock()
+
+	return watcher.book
+}
+
+// Run polls the orderbook immediately and then every interval until ctx is
+// done, and returns the context's error. Snapshots with the same LastChange as
+// the previous one, or no changed levels, are skipped. Failed polls are
+// reported to OnError.
+func (watcher *OrderbookWatcher) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := watcher.Poll(ctx); err != nil && watcher.OnError != nil && ctx.Err() == nil {
+			watcher.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll fetches the orderbook once, updates the current book and publishes the
+// changed levels, if any.
+func (watcher *OrderbookWatcher) Poll(ctx context.Context) error {
+	watcher.polling.Lock()
+	defer watcher.polling.Unlock()
+
+	book, err := watcher.api.GetOrderbook(ctx, watcher.market)
+	if err != nil {
+		return err
+	}
+
+	previous := watcher.Book()
+	if previous != nil && book.LastChange != 0 && book.LastChange == previous.LastChange {
+		return nil
+	}
+
+	watcher.mu.Lock()
+	watcher.book = book
+	watcher.mu.Unlock()
+
+	diff := DiffOrderbooks(previous, book)
+	if previous != nil && diff.IsEmpty() {
+		return nil
+	}
+
+	select {
+	case watcher.updates <- OrderbookUpdate{Market: watcher.market, Book: book, Diff: diff}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,117 @@
+package qtrade
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffOrderbooks(t *testing.T) {
+	from := NewOrderbook(
+		[]OrderbookLevel{level("0.99", "1"), level("0.98", "3")},
+		[]OrderbookLevel{level("1.01", "2")},
+		1)
+	to := NewOrderbook(
+		[]OrderbookLevel{level("0.99", "1"), level("0.98", "2"), level("0.97", "5")},
+		nil,
+		2)
+
+	assert.Equal(t, OrderbookDiff{
+		Bids: OrderbookLevels{level("0.98", "2"), level("0.97", "5")},
+		Asks: OrderbookLevels{level("1.01", "0")},
+	}, DiffOrderbooks(from, to))
+
+	assert.Equal(t, OrderbookDiff{Bids: to.Bids}, DiffOrderbooks(nil, to))
+	assert.True(t, DiffOrderbooks(to, to).IsEmpty())
+}
+
+func TestOrderbookWatcher_Poll(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	responses := []string{
+		`{"data": {"buy": {"0.01": "1"}, "sell": {"0.02": "1"}, "last_change": 1}}`,
+		`{"data": {"buy": {"0.01": "1"}, "sell": {"0.02": "1"}, "last_change": 1}}`,
+		`{"data": {"buy": {"0.01": "1"}, "sell": {"0.02": "1"}, "last_change": 2}}`,
+		`{"data": {"buy": {"0.01": "3"}, "sell": {}, "last_change": 3}}`,
+	}
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/orderbook/LTC_BTC",
+		func(req *http.Request) (*http.Response, error) {
+			response := responses[0]
+			responses = responses[1:]
+
+			return httpmock.NewStringResponse(200, response), nil
+		})
+
+	ctx := context.Background()
+	watcher := NewOrderbookWatcher(testClient, LTC_BTC)
+	assert.Nil(t, watcher.Book())
+
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, watcher.Poll(ctx))
+	}
+
+	// the repeated last_change and the unchanged levels are skipped
+	if assert.Len(t, watcher.Updates(), 2) {
+		first := <-watcher.Updates()
+		assert.Equal(t, LTC_BTC, first.Market)
+		assert.Equal(t, OrderbookDiff{
+			Bids: OrderbookLevels{level("0.01", "1")},
+			Asks: OrderbookLevels{level("0.02", "1")},
+		}, first.Diff)
+
+		second := <-watcher.Updates()
+		assert.Equal(t, OrderbookDiff{
+			Bids: OrderbookLevels{level("0.01", "3")},
+			Asks: OrderbookLevels{level("0.02", "0")},
+		}, second.Diff)
+		assert.Equal(t, 3, second.Book.LastChange)
+	}
+
+	assert.Equal(t, 3, watcher.Book().LastChange)
+	assert.Equal(t, 4, httpmock.GetTotalCallCount())
+}
+
+func TestOrderbookWatcher_Run(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/orderbook/LTC_BTC",
+		httpmock.NewStringResponder(500, ""))
+
+	client := NewPublicClient(Configuration{Endpoint: "http://localhost"}, WithRetryPolicy(nil))
+
+	errs := make(chan error, 10)
+	watcher := NewOrderbookWatcher(client, LTC_BTC)
+	watcher.OnError = func(err error) { errs <- err }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+
+	go func() { done <- watcher.Run(ctx, time.Millisecond*10) }()
+
+	select {
+	case err := <-errs:
+		assert.True(t, IsRetryable(err))
+	case <-time.After(time.Second):
+		t.Fatal("poll error was not reported")
+	}
+
+	httpmock.RegisterResponder("GET", "http://localhost/v1/orderbook/LTC_BTC",
+		httpmock.NewStringResponder(200, `{"data": {"buy": {"0.01": "1"}, "sell": {}, "last_change": 1}}`))
+
+	select {
+	case update := <-watcher.Updates():
+		assert.Equal(t, OrderbookLevels{level("0.01", "1")}, update.Book.Bids)
+	case <-time.After(time.Second):
+		t.Fatal("update was not published")
+	}
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+}